@@ -25,11 +25,16 @@ func newAlea(seed any) *alea {
 	if seed == nil {
 		seed = time.Now().UnixNano()
 	}
+	if c, ok := seed.(Clock); ok {
+		seed = c.Now().UnixNano()
+	}
 
 	seedStr := ""
 	switch s := seed.(type) {
 	case int:
 		seedStr = strconv.Itoa(s)
+	case int64:
+		seedStr = strconv.FormatInt(s, 10)
 	case string:
 		seedStr = s
 	}
@@ -79,7 +84,8 @@ func mash() func(string) float64 {
 type PRNG func() float64
 
 // Alea returns a deterministic PRNG seeded with the given value.
-// The seed can be a string, int, or nil (which uses the current time).
+// The seed can be a string, int, int64, a Clock (which uses its current
+// time) or nil (which uses time.Now).
 func Alea(seed any) PRNG {
 	xg := newAlea(seed)
 	prng := func() float64 {
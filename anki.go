@@ -0,0 +1,225 @@
+package fsrs
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// AnkiReviewKind is the type column of Anki's revlog table.
+type AnkiReviewKind int8
+
+const (
+	AnkiLearn AnkiReviewKind = iota
+	AnkiReview
+	AnkiRelearn
+	// AnkiFiltered is a review in a filtered deck, which only counts as a
+	// real review when the deck reschedules cards (non-zero factor).
+	AnkiFiltered
+	// AnkiManual marks a "forget" or "set due date" done by the user.
+	AnkiManual
+	// AnkiRescheduled marks a "set due date" in recent Anki versions.
+	AnkiRescheduled
+)
+
+// AnkiRevlog is one row of Anki's revlog table. ID is the review time in
+// Unix milliseconds and CID the card ID. Ivl and LastIvl are in days when
+// positive and in seconds when negative. Factor is the ease in permille.
+// Time is the answer time in milliseconds. JSON field names match Anki's
+// column names case-insensitively.
+type AnkiRevlog struct {
+	ID      int64          `json:"ID"`
+	CID     int64          `json:"CID"`
+	Ease    int            `json:"Ease"`
+	Ivl     int64          `json:"Ivl"`
+	LastIvl int64          `json:"LastIvl"`
+	Factor  int64          `json:"Factor"`
+	Time    int64          `json:"Time"`
+	Type    AnkiReviewKind `json:"Type"`
+}
+
+// ReadAnkiRevlogCSV reads revlog rows from CSV with a header row naming the
+// columns id, cid, ease, ivl, lastIvl, factor, time and type. Other columns,
+// such as usn, are ignored.
+func ReadAnkiRevlogCSV(r io.Reader) ([]AnkiRevlog, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := []string{"id", "cid", "ease", "ivl", "lastIvl", "factor", "time", "type"}
+	columns := make([]int, len(names))
+	for i, name := range names {
+		columns[i] = -1
+		for j, h := range header {
+			if h == name {
+				columns[i] = j
+			}
+		}
+		if columns[i] < 0 {
+			return nil, &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: anki revlog: missing column %s", name)}
+		}
+	}
+
+	var rows []AnkiRevlog
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		var v [8]int64
+		for i, c := range columns {
+			if c >= len(record) {
+				return nil, &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: anki revlog line %d: missing %s", line, names[i])}
+			}
+			if v[i], err = strconv.ParseInt(record[c], 10, 64); err != nil {
+				return nil, &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: anki revlog line %d: invalid %s %q", line, names[i], record[c])}
+			}
+		}
+		rows = append(rows, AnkiRevlog{
+			ID: v[0], CID: v[1], Ease: int(v[2]), Ivl: v[3], LastIvl: v[4], Factor: v[5], Time: v[6], Type: AnkiReviewKind(v[7]),
+		})
+	}
+}
+
+// ReadAnkiRevlogJSON reads revlog rows from either a JSON array of objects
+// or a stream of objects, one after another.
+func ReadAnkiRevlogJSON(r io.Reader) ([]AnkiRevlog, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		br.ReadByte()
+	}
+
+	dec := json.NewDecoder(br)
+	var rows []AnkiRevlog
+	if b, _ := br.Peek(1); b[0] == '[' {
+		if err := dec.Decode(&rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+	for {
+		var row AnkiRevlog
+		err := dec.Decode(&row)
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// AnkiReviewHistories groups revlog rows by card and converts them, in
+// review time order, to ReviewHistory sequences ready for [FSRS.Reschedule]:
+//
+//   - Learn, Review and Relearn rows, and Filtered rows with a non-zero
+//     factor, become graded reviews.
+//   - Filtered rows with a zero factor are cram reviews that did not affect
+//     scheduling and are skipped.
+//   - Manual rows with a zero factor are "forget" entries and become Manual
+//     entries in the New state.
+//   - Other Manual and Rescheduled rows are "set due date" entries and
+//     become Manual entries in the Review state, due Ivl days later.
+//
+// A history that does not start with a learning step or a forget is
+// incomplete, typically because older rows were deleted. Its memory state
+// is then estimated with [FSRS.MemoryStateFromSM2] from the factor and last
+// interval of the first row and given to a leading Manual entry, or to the
+// first entry when that is already a "set due date". Rows whose SM-2 data
+// cannot be converted are left as they are.
+func (f *FSRS) AnkiReviewHistories(rows []AnkiRevlog, sm2Retention float64) (map[int64][]ReviewHistory, error) {
+	byCard := map[int64][]AnkiRevlog{}
+	for _, row := range rows {
+		byCard[row.CID] = append(byCard[row.CID], row)
+	}
+	histories := make(map[int64][]ReviewHistory, len(byCard))
+	for cid, card := range byCard {
+		sort.SliceStable(card, func(i, j int) bool { return card[i].ID < card[j].ID })
+		var history []ReviewHistory
+		for _, row := range card {
+			reviewed := time.UnixMilli(row.ID).UTC()
+			switch row.Type {
+			case AnkiLearn, AnkiReview, AnkiRelearn, AnkiFiltered:
+				if row.Type == AnkiFiltered && row.Factor == 0 {
+					continue
+				}
+				if row.Ease < int(Again) || row.Ease > int(Easy) {
+					return nil, &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: anki revlog %d: invalid ease %d", row.ID, row.Ease)}
+				}
+				if len(history) == 0 && row.Type != AnkiLearn {
+					history = f.ankiStartingEntry(row, reviewed, sm2Retention)
+				}
+				history = append(history, ReviewHistory{Rating: Rating(row.Ease), Review: reviewed})
+			case AnkiManual, AnkiRescheduled:
+				if row.Type == AnkiManual && row.Factor == 0 {
+					history = append(history, ReviewHistory{Rating: Manual, Review: reviewed, State: StatePtr(New)})
+					continue
+				}
+				entry := ReviewHistory{
+					Rating: Manual,
+					Review: reviewed,
+					State:  StatePtr(Review),
+					Due:    reviewed.Add(time.Duration(max(row.Ivl, 0)) * 24 * time.Hour),
+				}
+				if len(history) == 0 {
+					if m, err := f.MemoryStateFromSM2(float64(row.Factor)/1000, float64(max(row.Ivl, 0)), sm2Retention); err == nil {
+						entry.Stability, entry.Difficulty = m.Stability, m.Difficulty
+					}
+				}
+				history = append(history, entry)
+			default:
+				return nil, &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: anki revlog %d: invalid type %d", row.ID, row.Type)}
+			}
+		}
+		if len(history) > 0 {
+			histories[cid] = history
+		}
+	}
+	return histories, nil
+}
+
+// ankiStartingEntry returns the Manual entry that stands in for the missing
+// history before row, placing the card in the Review state LastIvl days
+// before row with the memory state implied by its SM-2 data.
+func (f *FSRS) ankiStartingEntry(row AnkiRevlog, reviewed time.Time, sm2Retention float64) []ReviewHistory {
+	if row.LastIvl <= 0 {
+		return nil
+	}
+	m, err := f.MemoryStateFromSM2(float64(row.Factor)/1000, float64(row.LastIvl), sm2Retention)
+	if err != nil {
+		return nil
+	}
+	return []ReviewHistory{{
+		Rating:     Manual,
+		Review:     reviewed.Add(-time.Duration(row.LastIvl) * 24 * time.Hour),
+		State:      StatePtr(Review),
+		Due:        reviewed,
+		Stability:  m.Stability,
+		Difficulty: m.Difficulty,
+	}}
+}
@@ -0,0 +1,91 @@
+package fsrs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadAnkiRevlog(t *testing.T) {
+	csvInput := "id,cid,usn,ease,ivl,lastIvl,factor,time,type\n1719824400000,7,0,3,1,-600,2500,4000,0\n"
+	jsonInput := `[{"id":1719824400000,"cid":7,"ease":3,"ivl":1,"lastIvl":-600,"factor":2500,"time":4000,"type":0}]`
+	want := AnkiRevlog{ID: 1719824400000, CID: 7, Ease: 3, Ivl: 1, LastIvl: -600, Factor: 2500, Time: 4000, Type: AnkiLearn}
+
+	fromCSV, err := ReadAnkiRevlogCSV(strings.NewReader(csvInput))
+	if err != nil || len(fromCSV) != 1 || fromCSV[0] != want {
+		t.Errorf("CSV: got=%+v err=%v", fromCSV, err)
+	}
+	fromJSON, err := ReadAnkiRevlogJSON(strings.NewReader(jsonInput))
+	if err != nil || len(fromJSON) != 1 || fromJSON[0] != want {
+		t.Errorf("JSON array: got=%+v err=%v", fromJSON, err)
+	}
+	stream := strings.Trim(jsonInput, "[]") + "\n" + strings.Trim(jsonInput, "[]")
+	if rows, err := ReadAnkiRevlogJSON(strings.NewReader(stream)); err != nil || len(rows) != 2 {
+		t.Errorf("JSON stream: got=%+v err=%v", rows, err)
+	}
+	if _, err := ReadAnkiRevlogCSV(strings.NewReader("id,cid\n1,2\n")); !errors.Is(err, &Error{Code: ErrCodeInvalidInput}) {
+		t.Errorf("expected ErrCodeInvalidInput for missing columns, got=%v", err)
+	}
+}
+
+func TestAnkiReviewHistories(t *testing.T) {
+	f := NewFSRS(DefaultParam())
+	start := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	ms := func(days int) int64 { return start.AddDate(0, 0, days).UnixMilli() }
+	rows := []AnkiRevlog{
+		// Card 1: complete history with cram, set due date and forget.
+		{ID: ms(3), CID: 1, Ease: 3, Ivl: 8, LastIvl: 3, Factor: 2500, Type: AnkiReview},
+		{ID: ms(0), CID: 1, Ease: 3, Ivl: 3, LastIvl: -600, Factor: 2500, Type: AnkiLearn},
+		{ID: ms(4), CID: 1, Ease: 1, Ivl: 8, LastIvl: 8, Factor: 0, Type: AnkiFiltered},
+		{ID: ms(5), CID: 1, Ease: 0, Ivl: 20, LastIvl: 8, Factor: 2500, Type: AnkiRescheduled},
+		{ID: ms(6), CID: 1, Ease: 0, Ivl: 0, LastIvl: 20, Factor: 0, Type: AnkiManual},
+		{ID: ms(7), CID: 1, Ease: 4, Ivl: 4, LastIvl: 0, Factor: 2500, Type: AnkiLearn},
+		// Card 2: history starting with a review; older rows were deleted.
+		{ID: ms(10), CID: 2, Ease: 3, Ivl: 25, LastIvl: 10, Factor: 2500, Type: AnkiReview},
+	}
+	histories, err := f.AnkiReviewHistories(rows, 0.9)
+	if err != nil {
+		t.Fatalf("AnkiReviewHistories returned error: %v", err)
+	}
+
+	h1 := histories[1]
+	wantRatings := []Rating{Good, Good, Manual, Manual, Easy}
+	if len(h1) != len(wantRatings) {
+		t.Fatalf("card 1: expected %d entries, got=%+v", len(wantRatings), h1)
+	}
+	for i, r := range wantRatings {
+		if h1[i].Rating != r {
+			t.Errorf("card 1 entry %d: rating=%v want=%v", i, h1[i].Rating, r)
+		}
+	}
+	if *h1[2].State != Review || !h1[2].Due.Equal(start.AddDate(0, 0, 25)) {
+		t.Errorf("expected the set due date entry, got=%+v", h1[2])
+	}
+	if *h1[3].State != New {
+		t.Errorf("expected the forget entry, got=%+v", h1[3])
+	}
+
+	h2 := histories[2]
+	if len(h2) != 2 || h2[0].Rating != Manual || *h2[0].State != Review || h2[0].Stability <= 0 {
+		t.Fatalf("card 2: expected a leading SM-2 entry, got=%+v", h2)
+	}
+	if !h2[0].Review.Equal(start) || !h2[0].Due.Equal(start.AddDate(0, 0, 10)) {
+		t.Errorf("card 2: expected the entry to cover the last interval, got=%+v", h2[0])
+	}
+
+	for cid, h := range histories {
+		result, err := f.Reschedule(NewCard(start), h, RescheduleOptions{Now: start.AddDate(0, 0, 30)})
+		if err != nil {
+			t.Fatalf("card %d: Reschedule returned error: %v", cid, err)
+		}
+		last := result.Collections[len(result.Collections)-1].Card
+		if last.State != Review && last.State != Learning || last.Stability <= 0 {
+			t.Errorf("card %d: unexpected replayed card %+v", cid, last)
+		}
+	}
+
+	if _, err := f.AnkiReviewHistories([]AnkiRevlog{{ID: ms(0), CID: 3, Ease: 5}}, 0.9); !errors.Is(err, &Error{Code: ErrCodeInvalidInput}) {
+		t.Errorf("expected ErrCodeInvalidInput for an invalid ease, got=%v", err)
+	}
+}
@@ -31,7 +31,7 @@ func defaultDecayAndFactor() (float64, float64) {
 }
 
 func (p *Parameters) decayAndFactor() (float64, float64) {
-	if p.Validate() != nil {
+	if p.validateModel() != nil {
 		return defaultDecayAndFactor()
 	}
 
@@ -65,7 +65,20 @@ func (p *Parameters) nextInterval(s, elapsedDays float64) float64 {
 	if !p.EnableFuzz || interval < 2.5 {
 		return interval
 	}
-	return applyFuzz(interval, elapsedDays, p.MaximumInterval, p.seed)
+	return p.fuzzInterval(interval, elapsedDays)
+}
+
+// modifyInterval passes interval through p.IntervalModifier, if any, and
+// rounds and clamps the result to [1, MaximumInterval].
+func (p *Parameters) modifyInterval(interval float64, card Card, grade Rating, elapsedDays float64) float64 {
+	if p.IntervalModifier == nil {
+		return interval
+	}
+	modified := p.IntervalModifier(interval, card, grade, elapsedDays)
+	if math.IsNaN(modified) {
+		return interval
+	}
+	return max(min(math.Round(modified), p.MaximumInterval), 1)
 }
 
 func (p *Parameters) nextIntervalRaw(s float64) float64 {
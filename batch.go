@@ -0,0 +1,141 @@
+package fsrs
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchItem is one card processed by the batch APIs. Rating is only used by
+// [FSRS.NextBatch].
+type BatchItem struct {
+	Card   Card      `json:"Card"`
+	Now    time.Time `json:"Now"`
+	Rating Rating    `json:"Rating"`
+}
+
+// NextResult is the outcome of one item of [FSRS.NextBatch].
+type NextResult struct {
+	Info SchedulingInfo `json:"Info"`
+	Err  error          `json:"-"`
+}
+
+// RepeatResult is the outcome of one item of [FSRS.RepeatBatch].
+type RepeatResult struct {
+	Log RecordLog `json:"Log"`
+	Err error     `json:"-"`
+}
+
+// RetrievabilityResult is the outcome of one item of
+// [FSRS.RetrievabilityBatch].
+type RetrievabilityResult struct {
+	Retrievability float64 `json:"Retrievability"`
+	Err            error   `json:"-"`
+}
+
+// batchChunk is the number of consecutive items a worker claims at a time.
+const batchChunk = 256
+
+// NextBatch applies [FSRS.Next] to every item on up to workers goroutines,
+// or runtime.GOMAXPROCS(0) when workers <= 0. Each goroutine reuses a single
+// Scheduler. Results are in input order and carry their own error. If ctx is
+// cancelled before every item is processed, the remaining items get
+// ctx.Err() and so does the returned error.
+func (f *FSRS) NextBatch(ctx context.Context, items []BatchItem, workers int) ([]NextResult, error) {
+	results := make([]NextResult, len(items))
+	err := runBatch(ctx, len(items), workers, func() func(i int) {
+		cache := schedulerCache{f: f}
+		return func(i int) {
+			item := items[i]
+			results[i].Info, results[i].Err = f.next(item.Card, item.Now, item.Rating, cache.scheduler)
+		}
+	}, func(i int, err error) { results[i].Err = err })
+	return results, err
+}
+
+// RepeatBatch applies [FSRS.Repeat] to every item. See NextBatch.
+func (f *FSRS) RepeatBatch(ctx context.Context, items []BatchItem, workers int) ([]RepeatResult, error) {
+	results := make([]RepeatResult, len(items))
+	err := runBatch(ctx, len(items), workers, func() func(i int) {
+		cache := schedulerCache{f: f}
+		return func(i int) {
+			item := items[i]
+			results[i].Log, results[i].Err = f.repeat(item.Card, item.Now, cache.scheduler)
+		}
+	}, func(i int, err error) { results[i].Err = err })
+	return results, err
+}
+
+// RetrievabilityBatch applies [FSRS.Retrievability] to every item. See
+// NextBatch.
+func (f *FSRS) RetrievabilityBatch(ctx context.Context, items []BatchItem, workers int) ([]RetrievabilityResult, error) {
+	results := make([]RetrievabilityResult, len(items))
+	err := runBatch(ctx, len(items), workers, func() func(i int) {
+		return func(i int) {
+			results[i].Retrievability, results[i].Err = f.Retrievability(items[i].Card, items[i].Now)
+		}
+	}, func(i int, err error) { results[i].Err = err })
+	return results, err
+}
+
+// schedulerCache hands out one Scheduler, reset for every review. It must
+// only be used by a single goroutine.
+type schedulerCache struct {
+	f *FSRS
+	s *Scheduler
+}
+
+func (c *schedulerCache) scheduler(card Card, now time.Time) *Scheduler {
+	if c.s == nil {
+		c.s = c.f.scheduler(card, now)
+	} else {
+		c.s.reset(&c.f.Parameters, card, now)
+	}
+	return c.s
+}
+
+// runBatch calls the function built by newWorker for every index in [0, n),
+// with one such function per goroutine. Workers claim batchChunk indices at
+// a time and stop at the next chunk once ctx is done; skip is then called
+// with ctx.Err() for every index left unprocessed.
+func runBatch(ctx context.Context, n, workers int, newWorker func() func(i int), skip func(i int, err error)) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, (n+batchChunk-1)/batchChunk)
+
+	var next atomic.Int64
+	var skipped atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			process := newWorker()
+			for {
+				start := int(next.Add(batchChunk)) - batchChunk
+				if start >= n {
+					return
+				}
+				end := min(start+batchChunk, n)
+				if err := ctx.Err(); err != nil {
+					skipped.Store(true)
+					for i := start; i < end; i++ {
+						skip(i, err)
+					}
+					continue
+				}
+				for i := start; i < end; i++ {
+					process(i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if skipped.Load() {
+		return ctx.Err()
+	}
+	return nil
+}
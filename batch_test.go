@@ -0,0 +1,92 @@
+package fsrs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func batchItems(n int) []BatchItem {
+	f := NewFSRS(DefaultParam())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	items := make([]BatchItem, n)
+	for i := range items {
+		card := NewCard(now)
+		for r := 0; r < i%5; r++ {
+			info, _ := f.Next(card, card.Due, Rating(1+(i+r)%4))
+			card = info.Card
+		}
+		items[i] = BatchItem{Card: card, Now: card.Due.Add(time.Duration(i) * time.Hour), Rating: Rating(1 + i%4)}
+	}
+	items[7].Rating = 9
+	items[11].Card.Suspended = true
+	return items
+}
+
+func TestNextBatch(t *testing.T) {
+	for _, shortTerm := range []bool{true, false} {
+		p := DefaultParam()
+		p.EnableShortTerm = shortTerm
+		p.EnableFuzz = true
+		f := NewFSRS(p)
+		items := batchItems(1000)
+
+		results, err := f.NextBatch(context.Background(), items, 4)
+		if err != nil {
+			t.Fatalf("NextBatch returned error: %v", err)
+		}
+		repeats, err := f.RepeatBatch(context.Background(), items, 3)
+		if err != nil {
+			t.Fatalf("RepeatBatch returned error: %v", err)
+		}
+		rs, err := f.RetrievabilityBatch(context.Background(), items, 0)
+		if err != nil {
+			t.Fatalf("RetrievabilityBatch returned error: %v", err)
+		}
+		for i, item := range items {
+			info, err := f.Next(item.Card, item.Now, item.Rating)
+			if !reflect.DeepEqual(results[i].Info, info) || !errors.Is(results[i].Err, err) || (err == nil) != (results[i].Err == nil) {
+				t.Fatalf("shortTerm=%v item %d: NextBatch=%+v/%v, Next=%+v/%v", shortTerm, i, results[i].Info, results[i].Err, info, err)
+			}
+			log, err := f.Repeat(item.Card, item.Now)
+			if !reflect.DeepEqual(repeats[i].Log, log) || (err == nil) != (repeats[i].Err == nil) {
+				t.Fatalf("shortTerm=%v item %d: RepeatBatch differs from Repeat", shortTerm, i)
+			}
+			r, _ := f.Retrievability(item.Card, item.Now)
+			if rs[i].Retrievability != r {
+				t.Fatalf("item %d: RetrievabilityBatch=%v, Retrievability=%v", i, rs[i].Retrievability, r)
+			}
+		}
+		if !errors.Is(results[11].Err, ErrCardSuspended) {
+			t.Errorf("expected a per-item ErrCardSuspended, got=%v", results[11].Err)
+		}
+	}
+}
+
+func TestNextBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := NewFSRS(DefaultParam()).NextBatch(ctx, batchItems(20), 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got=%v", err)
+	}
+	for i, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("item %d: expected context.Canceled, got=%v", i, r.Err)
+		}
+	}
+	if results, err := NewFSRS(DefaultParam()).NextBatch(context.Background(), nil, 0); err != nil || len(results) != 0 {
+		t.Errorf("expected an empty batch to succeed, got=%v %v", results, err)
+	}
+}
+
+func BenchmarkNextBatch(b *testing.B) {
+	f := NewFSRS(DefaultParam())
+	items := batchItems(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.NextBatch(context.Background(), items, 0)
+	}
+}
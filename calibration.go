@@ -0,0 +1,136 @@
+package fsrs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// CalibrationBucket holds the reviews whose bucketed value fell in
+// [Lower, Upper). Predicted is the mean predicted retrievability, Observed
+// the fraction of reviews not rated Again and Gap is Observed - Predicted.
+type CalibrationBucket struct {
+	Label     string  `json:"Label"`
+	Lower     float64 `json:"Lower"`
+	Upper     float64 `json:"Upper"`
+	Count     int     `json:"Count"`
+	Predicted float64 `json:"Predicted"`
+	Observed  float64 `json:"Observed"`
+	Gap       float64 `json:"Gap"`
+}
+
+// CalibrationReport compares predicted retrievability with observed recall,
+// broken down by predicted retrievability, stability, difficulty and state.
+// Only non-empty buckets are listed. See [FSRS.Calibration].
+type CalibrationReport struct {
+	Count            int                 `json:"Count"`
+	Predicted        float64             `json:"Predicted"`
+	Observed         float64             `json:"Observed"`
+	ByRetrievability []CalibrationBucket `json:"ByRetrievability"`
+	ByStability      []CalibrationBucket `json:"ByStability"`
+	ByDifficulty     []CalibrationBucket `json:"ByDifficulty"`
+	ByState          []CalibrationBucket `json:"ByState"`
+}
+
+// Calibration predicts recall for every graded review in logs with
+// [Parameters.ForgettingCurve], using the stability recorded in the log and the
+// days elapsed since the previous review, and buckets the predictions.
+//
+// Retrievability is split into bins equal-width buckets (10 when bins <= 0),
+// stability into power-of-two buckets, difficulty into unit buckets and state
+// by value. Manual logs and logs of New cards carry no prediction and are
+// skipped, as are same-day reviews such as short-term learning steps, for
+// which the forgetting curve always predicts 1, matching [FSRS.Evaluate].
+func (f *FSRS) Calibration(logs []ReviewLog, bins int) CalibrationReport {
+	if bins <= 0 {
+		bins = 10
+	}
+	byR := make([]CalibrationBucket, bins)
+	for i := range byR {
+		lo, hi := float64(i)/float64(bins), float64(i+1)/float64(bins)
+		byR[i] = CalibrationBucket{Label: fmt.Sprintf("[%.2f, %.2f)", lo, hi), Lower: lo, Upper: hi}
+	}
+	var byS []CalibrationBucket
+	for lo := 0.0; lo < sMax; {
+		hi := math.Max(1, lo*2)
+		byS = append(byS, CalibrationBucket{Label: fmt.Sprintf("[%g, %g)", lo, hi), Lower: lo, Upper: hi})
+		lo = hi
+	}
+	var byD []CalibrationBucket
+	for d := dMin; d < dMax; d++ {
+		byD = append(byD, CalibrationBucket{Label: fmt.Sprintf("[%g, %g)", d, d+1), Lower: d, Upper: d + 1})
+	}
+	byState := []CalibrationBucket{
+		{Label: Learning.String(), Lower: float64(Learning), Upper: float64(Learning + 1)},
+		{Label: Review.String(), Lower: float64(Review), Upper: float64(Review + 1)},
+		{Label: Relearning.String(), Lower: float64(Relearning), Upper: float64(Relearning + 1)},
+	}
+
+	var report CalibrationReport
+	for _, log := range logs {
+		if log.Rating < Again || log.Rating > Easy || log.State == New || !isValidState(log.State) {
+			continue
+		}
+		if !isFinite(log.Stability) || log.Stability < sMin {
+			continue
+		}
+		elapsed := float64(f.daysBetween(log.Due, log.Review))
+		if elapsed <= 0 {
+			continue
+		}
+		r := f.ForgettingCurve(elapsed, log.Stability)
+		y := 0.0
+		if log.Rating > Again {
+			y = 1
+		}
+
+		report.Count++
+		report.Predicted += r
+		report.Observed += y
+		addToBucket(byR, min(int(r*float64(bins)), bins-1), r, y)
+		sIdx := 0
+		if log.Stability >= 1 {
+			sIdx = min(int(math.Log2(log.Stability))+1, len(byS)-1)
+		}
+		addToBucket(byS, sIdx, r, y)
+		addToBucket(byD, min(max(int(log.Difficulty-dMin), 0), len(byD)-1), r, y)
+		addToBucket(byState, int(log.State-Learning), r, y)
+	}
+	if report.Count > 0 {
+		report.Predicted /= float64(report.Count)
+		report.Observed /= float64(report.Count)
+	}
+	report.ByRetrievability = finishBuckets(byR)
+	report.ByStability = finishBuckets(byS)
+	report.ByDifficulty = finishBuckets(byD)
+	report.ByState = finishBuckets(byState)
+	return report
+}
+
+// WriteJSON writes the report to w as indented JSON.
+func (r CalibrationReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func addToBucket(buckets []CalibrationBucket, i int, predicted, observed float64) {
+	buckets[i].Count++
+	buckets[i].Predicted += predicted
+	buckets[i].Observed += observed
+}
+
+func finishBuckets(buckets []CalibrationBucket) []CalibrationBucket {
+	out := make([]CalibrationBucket, 0, len(buckets))
+	for _, b := range buckets {
+		if b.Count == 0 {
+			continue
+		}
+		b.Predicted /= float64(b.Count)
+		b.Observed /= float64(b.Count)
+		b.Gap = b.Observed - b.Predicted
+		out = append(out, b)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package fsrs
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCalibration(t *testing.T) {
+	f := NewFSRS(DefaultParam())
+	last := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	logs := []ReviewLog{
+		{Rating: Good, State: New, Due: last, Review: last},
+		{Rating: Manual, State: Review, Due: last, Review: last.Add(48 * time.Hour), Stability: 5, Difficulty: 5},
+		{Rating: Good, State: Review, Due: last, Review: last.Add(48 * time.Hour), Stability: 5, Difficulty: 5.5},
+		{Rating: Again, State: Review, Due: last, Review: last.Add(48 * time.Hour), Stability: 5, Difficulty: 5.5},
+		{Rating: Good, State: Relearning, Due: last, Review: last.Add(24 * time.Hour), Stability: 0.5, Difficulty: 9},
+		{Rating: Again, State: Learning, Due: last, Review: last.Add(10 * time.Minute), Stability: 2, Difficulty: 5},
+	}
+
+	report := f.Calibration(logs, 10)
+	if report.Count != 3 {
+		t.Fatalf("expected 3 graded reviews, got=%d", report.Count)
+	}
+	if math.Abs(report.Observed-2.0/3) > 1e-9 {
+		t.Errorf("expected observed recall 2/3, got=%v", report.Observed)
+	}
+
+	r := f.ForgettingCurve(2, 5)
+	if len(report.ByDifficulty) != 2 || report.ByDifficulty[0].Count != 2 || report.ByDifficulty[0].Lower != 5 {
+		t.Fatalf("unexpected difficulty buckets: %+v", report.ByDifficulty)
+	}
+	b := report.ByDifficulty[0]
+	if math.Abs(b.Predicted-r) > 1e-9 || b.Observed != 0.5 || math.Abs(b.Gap-(0.5-r)) > 1e-9 {
+		t.Errorf("unexpected difficulty bucket: %+v", b)
+	}
+
+	if len(report.ByStability) != 2 || report.ByStability[0].Upper != 1 || report.ByStability[1].Lower != 4 {
+		t.Errorf("unexpected stability buckets: %+v", report.ByStability)
+	}
+	if len(report.ByState) != 2 || report.ByState[0].Label != "Review" || report.ByState[1].Label != "Relearning" {
+		t.Errorf("unexpected state buckets: %+v", report.ByState)
+	}
+	total := 0
+	for _, b := range report.ByRetrievability {
+		total += b.Count
+		if b.Predicted < b.Lower || b.Predicted >= b.Upper {
+			t.Errorf("mean prediction %v outside bucket %s", b.Predicted, b.Label)
+		}
+	}
+	if total != report.Count {
+		t.Errorf("expected retrievability buckets to cover %d reviews, got=%d", report.Count, total)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+	var decoded CalibrationReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.Count != report.Count || len(decoded.ByState) != len(report.ByState) {
+		t.Errorf("JSON round trip mismatch: %+v", decoded)
+	}
+}
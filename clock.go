@@ -0,0 +1,53 @@
+package fsrs
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to the APIs that default to now, such as
+// [FSRS.NewCard] and [FSRS.Reschedule] without RescheduleOptions.Now.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock returns a Clock backed by time.Now. It is used when FSRS.Clock
+// is nil.
+func SystemClock() Clock { return systemClock{} }
+
+// FakeClock is a Clock whose time only changes through Set and Advance.
+// It is intended for tests and is safe for concurrent use.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock frozen at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
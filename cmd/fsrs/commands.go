@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	fsrs "github.com/open-spaced-repetition/go-fsrs/v4"
+)
+
+func cmdNext(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("next", flag.ContinueOnError)
+	paramsPath := fs.String("params", "", "JSON parameters file")
+	now := timeFlag(fs)
+	rating := fs.String("rating", "", "rating: 1-4 or Again, Hard, Good, Easy")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	grade, err := parseRating(*rating)
+	if err != nil {
+		return err
+	}
+	f, err := loadScheduler(*paramsPath)
+	if err != nil {
+		return err
+	}
+	card, err := readCard(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	info, err := f.Next(card, now(), grade)
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, info)
+}
+
+func cmdRetrievability(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("retrievability", flag.ContinueOnError)
+	paramsPath := fs.String("params", "", "JSON parameters file")
+	now := timeFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f, err := loadScheduler(*paramsPath)
+	if err != nil {
+		return err
+	}
+	card, err := readCard(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	r, err := f.Retrievability(card, now())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, strconv.FormatFloat(r, 'f', -1, 64))
+	return err
+}
+
+// cmdReplay prints the memory state after every graded review of each card
+// in a benchmark-format revlog, in card ID order.
+func cmdReplay(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	paramsPath := fs.String("params", "", "JSON parameters file")
+	only := fs.Int64("card", 0, "replay only the card with this ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f, err := loadScheduler(*paramsPath)
+	if err != nil {
+		return err
+	}
+	in, closeIn, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer closeIn()
+	entries, err := fsrs.NewRevlogReader(in).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	histories := f.RevlogHistories(entries)
+	ids := make([]int64, 0, len(histories))
+	for id := range histories {
+		if *only == 0 || id == *only {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "card_id\treview\trating\tdelta_t\tstability\tdifficulty")
+	for _, id := range ids {
+		history := histories[id]
+		states, err := f.HistoricalMemoryStates(history, nil)
+		if err != nil {
+			return fmt.Errorf("card %d: %w", id, err)
+		}
+		for i, s := range states {
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%g\t%.4f\t%.4f\n", id, i+1, history[i].Rating, history[i].DeltaT, s.Stability, s.Difficulty)
+		}
+	}
+	return tw.Flush()
+}
+
+func cmdMigrateWeights(args []string, _ io.Reader, stdout io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: fsrs migrate-weights w1,w2,...")
+	}
+	var weights []float64
+	for _, s := range strings.Split(strings.Trim(args[0], "[] "), ",") {
+		w, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return fmt.Errorf("invalid weight %q", s)
+		}
+		weights = append(weights, w)
+	}
+	migrated, err := fsrs.MigrateWeights(weights)
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, migrated)
+}
+
+func cmdValidateParams(args []string, _ io.Reader, stdout io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: fsrs validate-params params.json")
+	}
+	p, err := loadParameters(args[0])
+	if err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, "ok")
+	return err
+}
+
+// timeFlag registers -now and returns a function yielding its value, or the
+// current time when it is unset.
+func timeFlag(fs *flag.FlagSet) func() time.Time {
+	var t time.Time
+	fs.Func("now", "review time in RFC 3339 (default now)", func(s string) error {
+		var err error
+		t, err = time.Parse(time.RFC3339, s)
+		return err
+	})
+	return func() time.Time {
+		if t.IsZero() {
+			return time.Now()
+		}
+		return t
+	}
+}
+
+func parseRating(s string) (fsrs.Rating, error) {
+	for _, r := range []fsrs.Rating{fsrs.Again, fsrs.Hard, fsrs.Good, fsrs.Easy} {
+		if strings.EqualFold(s, r.String()) || s == strconv.Itoa(int(r)) {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid rating %q", s)
+}
+
+// loadParameters reads JSON parameters from path on top of the defaults. An
+// empty path yields the defaults.
+func loadParameters(path string) (fsrs.Parameters, error) {
+	p := fsrs.DefaultParam()
+	if path == "" {
+		return p, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// loadScheduler is like loadParameters but rejects invalid parameters
+// instead of letting fsrs.NewFSRS replace them with the defaults.
+func loadScheduler(path string) (*fsrs.FSRS, error) {
+	p, err := loadParameters(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return fsrs.NewFSRS(p), nil
+}
+
+func openInput(path string, stdin io.Reader) (io.Reader, func(), error) {
+	if path == "" || path == "-" {
+		return stdin, func() {}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func() { file.Close() }, nil
+}
+
+func readCard(path string, stdin io.Reader) (fsrs.Card, error) {
+	in, closeIn, err := openInput(path, stdin)
+	if err != nil {
+		return fsrs.Card{}, err
+	}
+	defer closeIn()
+	var card fsrs.Card
+	if err := json.NewDecoder(in).Decode(&card); err != nil {
+		return fsrs.Card{}, fmt.Errorf("reading card: %w", err)
+	}
+	return card, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
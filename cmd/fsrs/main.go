@@ -0,0 +1,50 @@
+// Command fsrs exposes the scheduler on the command line for debugging cards
+// and parameters by hand.
+//
+// Usage:
+//
+//	fsrs next [-params file] [-now time] -rating rating [card.json]
+//	fsrs retrievability [-params file] [-now time] [card.json]
+//	fsrs replay [-params file] [-card id] revlog.csv
+//	fsrs migrate-weights w1,w2,...
+//	fsrs validate-params params.json
+//
+// Cards are read as JSON from the named file or from standard input when no
+// file is given, and results are written as JSON. Parameter files hold JSON
+// Parameters applied on top of the defaults. Times are RFC 3339 and default
+// to the current time. Ratings are 1-4 or Again, Hard, Good and Easy. The
+// revlog read by replay is the CSV layout of the FSRS benchmark.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "fsrs:", err)
+		os.Exit(1)
+	}
+}
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]func(args []string, stdin io.Reader, stdout io.Writer) error{
+	"next":            cmdNext,
+	"retrievability":  cmdRetrievability,
+	"replay":          cmdReplay,
+	"migrate-weights": cmdMigrateWeights,
+	"validate-params": cmdValidateParams,
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: fsrs <next|retrievability|replay|migrate-weights|validate-params> [flags]")
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	return cmd(args[1:], stdin, stdout)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	fsrs "github.com/open-spaced-repetition/go-fsrs/v4"
+)
+
+func runCLI(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &out)
+	return out.String(), err
+}
+
+func TestNextAndRetrievability(t *testing.T) {
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	cardJSON, _ := json.Marshal(fsrs.NewCard(now))
+
+	out, err := runCLI(t, string(cardJSON), "next", "-now", "2024-07-01T09:00:00Z", "-rating", "easy")
+	if err != nil {
+		t.Fatalf("next returned error: %v", err)
+	}
+	var info fsrs.SchedulingInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := fsrs.NewFSRS(fsrs.DefaultParam()).Next(fsrs.NewCard(now), now, fsrs.Easy)
+	if !info.Card.Due.Equal(want.Card.Due) || info.Card.Stability != want.Card.Stability {
+		t.Errorf("next: got=%+v want=%+v", info.Card, want.Card)
+	}
+
+	reviewed, _ := json.Marshal(info.Card)
+	out, err = runCLI(t, string(reviewed), "retrievability", "-now", info.Card.Due.Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("retrievability returned error: %v", err)
+	}
+	if !strings.HasPrefix(out, "0.9") {
+		t.Errorf("expected retrievability near the desired retention at the due date, got=%q", out)
+	}
+
+	if _, err := runCLI(t, string(cardJSON), "next", "-rating", "5"); err == nil {
+		t.Error("expected an invalid rating to fail")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	revlog := "card_id,review_time,review_rating,review_state,review_duration\n" +
+		"1,1719824400000,3,0,0\n" +
+		"1,1720083600000,3,2,0\n" +
+		"2,1719824400000,1,0,0\n"
+	out, err := runCLI(t, revlog, "replay", "-card", "1")
+	if err != nil {
+		t.Fatalf("replay returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], "1 ") || !strings.Contains(lines[2], "Good") {
+		t.Errorf("expected a header and two reviews of card 1, got:\n%s", out)
+	}
+}
+
+func TestMigrateWeightsAndValidateParams(t *testing.T) {
+	defaults := fsrs.DefaultWeights()
+	var v5 [19]float64
+	copy(v5[:], defaults[:19])
+	var parts []string
+	for _, w := range v5 {
+		b, _ := json.Marshal(w)
+		parts = append(parts, string(b))
+	}
+	out, err := runCLI(t, "", "migrate-weights", strings.Join(parts, ","))
+	if err != nil {
+		t.Fatalf("migrate-weights returned error: %v", err)
+	}
+	var w fsrs.Weights
+	if err := json.Unmarshal([]byte(out), &w); err != nil || w != fsrs.ConvertV5Weights(v5) {
+		t.Errorf("migrate-weights: got=%q err=%v", out, err)
+	}
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(good, []byte(`{"RequestRetention": 0.85}`), 0o644)
+	os.WriteFile(bad, []byte(`{"RequestRetention": 1.5}`), 0o644)
+	if out, err := runCLI(t, "", "validate-params", good); err != nil || out != "ok\n" {
+		t.Errorf("validate-params good: out=%q err=%v", out, err)
+	}
+	if _, err := runCLI(t, "", "validate-params", bad); err == nil {
+		t.Error("expected invalid parameters to fail validation")
+	}
+	if _, err := runCLI(t, "", "bogus"); err == nil {
+		t.Error("expected an unknown command to fail")
+	}
+}
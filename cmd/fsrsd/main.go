@@ -0,0 +1,50 @@
+// Command fsrsd serves the FSRS scheduler over HTTP with JSON bodies, so
+// clients in any language schedule cards exactly as the Go library does.
+//
+// Usage:
+//
+//	fsrsd [-addr :8080] [-profiles profiles.json]
+//
+// The endpoints are POST /v1/repeat, /v1/next, /v1/retrievability,
+// /v1/rollback, /v1/forget, /v1/reschedule, /v1/memory-state and
+// /v1/optimal-retention, which takes an optional simulator "Config" and
+// stops when the client goes away. Every body
+// may carry "Profile", the name of a parameter set from the profiles file,
+// "Parameters", overriding individual fields of the profile or of the
+// defaults, and "Timezone", an IANA name used to count review days. The
+// remaining fields are the arguments of the matching FSRS method, e.g.
+//
+//	POST /v1/next
+//	{"Profile": "default", "Card": {...}, "Now": "2024-07-01T09:00:00Z", "Rating": 3}
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	fsrs "github.com/open-spaced-repetition/go-fsrs/v4"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	profilesPath := flag.String("profiles", "", "JSON file mapping profile names to Parameters")
+	flag.Parse()
+
+	profiles := map[string]fsrs.Parameters{}
+	if *profilesPath != "" {
+		var err error
+		if profiles, err = loadProfiles(*profilesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newHandler(profiles),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("fsrsd listening on %s with %d profiles", *addr, len(profiles))
+	log.Fatal(srv.ListenAndServe())
+}
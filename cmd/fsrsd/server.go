@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	fsrs "github.com/open-spaced-repetition/go-fsrs/v4"
+)
+
+// maxBodyBytes bounds the size of a request body.
+const maxBodyBytes = 1 << 20
+
+// scope is the part of every request that selects the parameters. Parameters
+// are applied on top of the named Profile, or of the defaults when Profile
+// is empty, so a request only needs the fields it wants to change. Timezone
+// is an IANA name overriding Parameters.Location.
+type scope struct {
+	Profile    string          `json:"Profile"`
+	Parameters json.RawMessage `json:"Parameters"`
+	Timezone   string          `json:"Timezone"`
+}
+
+type repeatRequest struct {
+	Card fsrs.Card `json:"Card"`
+	Now  time.Time `json:"Now"`
+}
+
+type nextRequest struct {
+	Card   fsrs.Card   `json:"Card"`
+	Now    time.Time   `json:"Now"`
+	Rating fsrs.Rating `json:"Rating"`
+}
+
+type rollbackRequest struct {
+	Card      fsrs.Card      `json:"Card"`
+	ReviewLog fsrs.ReviewLog `json:"ReviewLog"`
+}
+
+type forgetRequest struct {
+	Card       fsrs.Card `json:"Card"`
+	Now        time.Time `json:"Now"`
+	ResetCount bool      `json:"ResetCount"`
+}
+
+type rescheduleRequest struct {
+	Card    fsrs.Card              `json:"Card"`
+	Reviews []fsrs.ReviewHistory   `json:"Reviews"`
+	Options fsrs.RescheduleOptions `json:"Options"`
+}
+
+type memoryStateRequest struct {
+	History       fsrs.ReviewEntries `json:"History"`
+	StartingState *fsrs.MemoryState  `json:"StartingState"`
+}
+
+// optimalRetentionRequest carries the simulator settings, applied on top of
+// fsrs.DefaultSimulatorConfig.
+type optimalRetentionRequest struct {
+	Config json.RawMessage `json:"Config"`
+}
+
+type optimalRetentionResponse struct {
+	OptimalRetention float64 `json:"OptimalRetention"`
+}
+
+type retrievabilityResponse struct {
+	Retrievability float64 `json:"Retrievability"`
+}
+
+type errorResponse struct {
+	Code    fsrs.ErrorCode `json:"Code"`
+	Message string         `json:"Message"`
+}
+
+// httpError is an error with the status code it should be reported with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+func (e *httpError) Unwrap() error { return e.err }
+
+// newHandler returns the HTTP API. Every endpoint takes a POST with a JSON
+// body and answers with JSON; errors are reported as {"Code", "Message"},
+// where Code is the fsrs.ErrorCode or 0 for errors outside the library.
+func newHandler(profiles map[string]fsrs.Parameters) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /v1/repeat", endpoint(profiles, func(_ context.Context, f *fsrs.FSRS, req repeatRequest) (any, error) {
+		return f.Repeat(req.Card, req.Now)
+	}))
+	mux.Handle("POST /v1/next", endpoint(profiles, func(_ context.Context, f *fsrs.FSRS, req nextRequest) (any, error) {
+		return f.Next(req.Card, req.Now, req.Rating)
+	}))
+	mux.Handle("POST /v1/retrievability", endpoint(profiles, func(_ context.Context, f *fsrs.FSRS, req repeatRequest) (any, error) {
+		r, err := f.Retrievability(req.Card, req.Now)
+		return retrievabilityResponse{Retrievability: r}, err
+	}))
+	mux.Handle("POST /v1/rollback", endpoint(profiles, func(_ context.Context, f *fsrs.FSRS, req rollbackRequest) (any, error) {
+		return f.Rollback(req.Card, req.ReviewLog)
+	}))
+	mux.Handle("POST /v1/forget", endpoint(profiles, func(_ context.Context, f *fsrs.FSRS, req forgetRequest) (any, error) {
+		return f.Forget(req.Card, req.Now, req.ResetCount), nil
+	}))
+	mux.Handle("POST /v1/reschedule", endpoint(profiles, func(_ context.Context, f *fsrs.FSRS, req rescheduleRequest) (any, error) {
+		return f.Reschedule(req.Card, req.Reviews, req.Options)
+	}))
+	mux.Handle("POST /v1/memory-state", endpoint(profiles, func(_ context.Context, f *fsrs.FSRS, req memoryStateRequest) (any, error) {
+		return f.MemoryState(req.History, req.StartingState)
+	}))
+	mux.Handle("POST /v1/optimal-retention", endpoint(profiles, func(ctx context.Context, f *fsrs.FSRS, req optimalRetentionRequest) (any, error) {
+		config := fsrs.DefaultSimulatorConfig()
+		if len(req.Config) > 0 {
+			if err := json.Unmarshal(req.Config, &config); err != nil {
+				return nil, &httpError{status: http.StatusBadRequest, err: err}
+			}
+		}
+		retention, err := fsrs.OptimalRetentionContext(ctx, f.Parameters, config)
+		return optimalRetentionResponse{OptimalRetention: retention}, err
+	}))
+	return mux
+}
+
+// endpoint decodes the body into the scope and into T, builds the scheduler
+// and writes the result of fn, which runs with the request's context.
+func endpoint[T any](profiles map[string]fsrs.Parameters, fn func(ctx context.Context, f *fsrs.FSRS, req T) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			writeError(w, &httpError{status: http.StatusRequestEntityTooLarge, err: err})
+			return
+		}
+		var sc scope
+		var req T
+		if err := json.Unmarshal(body, &sc); err != nil {
+			writeError(w, &httpError{status: http.StatusBadRequest, err: err})
+			return
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, &httpError{status: http.StatusBadRequest, err: err})
+			return
+		}
+		f, err := sc.scheduler(profiles)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := fn(r.Context(), f, req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+}
+
+// scheduler builds the FSRS instance selected by sc. Unlike fsrs.NewFSRS,
+// invalid parameters are rejected rather than reset to the defaults.
+func (sc scope) scheduler(profiles map[string]fsrs.Parameters) (*fsrs.FSRS, error) {
+	p := fsrs.DefaultParam()
+	if sc.Profile != "" {
+		profile, ok := profiles[sc.Profile]
+		if !ok {
+			return nil, &httpError{status: http.StatusNotFound, err: fmt.Errorf("unknown profile %q", sc.Profile)}
+		}
+		p = profile
+		p.LearningSteps = append([]float64(nil), p.LearningSteps...)
+		p.RelearningSteps = append([]float64(nil), p.RelearningSteps...)
+		p.EasyDays = append([]float64(nil), p.EasyDays...)
+	}
+	if len(sc.Parameters) > 0 {
+		if err := json.Unmarshal(sc.Parameters, &p); err != nil {
+			return nil, &httpError{status: http.StatusBadRequest, err: err}
+		}
+	}
+	if sc.Timezone != "" {
+		loc, err := time.LoadLocation(sc.Timezone)
+		if err != nil {
+			return nil, &httpError{status: http.StatusBadRequest, err: err}
+		}
+		p.Location = loc
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return fsrs.NewFSRS(p), nil
+}
+
+// loadProfiles reads a JSON object mapping profile names to Parameters. Each
+// profile is applied on top of the defaults.
+func loadProfiles(path string) (map[string]fsrs.Parameters, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	profiles := make(map[string]fsrs.Parameters, len(raw))
+	for name, msg := range raw {
+		p := fsrs.DefaultParam()
+		if err := json.Unmarshal(msg, &p); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+		profiles[name] = p
+	}
+	return profiles, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	var he *httpError
+	if errors.As(err, &he) {
+		status = he.status
+	} else if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusServiceUnavailable
+	}
+	resp := errorResponse{Message: err.Error()}
+	var fe *fsrs.Error
+	if errors.As(err, &fe) {
+		resp.Code = fe.Code
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
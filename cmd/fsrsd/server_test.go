@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	fsrs "github.com/open-spaced-repetition/go-fsrs/v4"
+)
+
+func post(t *testing.T, h http.Handler, path string, body any, out any) int {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s: invalid response %q: %v", path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestServerMatchesLibrary(t *testing.T) {
+	p := fsrs.DefaultParam()
+	p.RequestRetention = 0.85
+	h := newHandler(map[string]fsrs.Parameters{"strict": p})
+	f := fsrs.NewFSRS(p)
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	card := fsrs.NewCard(now)
+
+	var next fsrs.SchedulingInfo
+	if code := post(t, h, "/v1/next", map[string]any{"Profile": "strict", "Card": card, "Now": now, "Rating": fsrs.Good}, &next); code != http.StatusOK {
+		t.Fatalf("next: status %d", code)
+	}
+	want, _ := f.Next(card, now, fsrs.Good)
+	if !next.Card.Due.Equal(want.Card.Due) || next.Card.Stability != want.Card.Stability || next.ReviewLog.Rating != fsrs.Good {
+		t.Errorf("next: got=%+v want=%+v", next, want)
+	}
+
+	var record fsrs.RecordLog
+	if code := post(t, h, "/v1/repeat", map[string]any{"Parameters": map[string]any{"RequestRetention": 0.85}, "Card": card, "Now": now}, &record); code != http.StatusOK {
+		t.Fatalf("repeat: status %d", code)
+	}
+	if len(record) != 4 || record[fsrs.Good].Card.Stability != want.Card.Stability {
+		t.Errorf("repeat: got=%+v", record)
+	}
+
+	later := next.Card.Due.AddDate(0, 0, 3)
+	var r retrievabilityResponse
+	post(t, h, "/v1/retrievability", map[string]any{"Profile": "strict", "Card": next.Card, "Now": later}, &r)
+	if wantR, _ := f.Retrievability(want.Card, later); r.Retrievability != wantR {
+		t.Errorf("retrievability: got=%v want=%v", r.Retrievability, wantR)
+	}
+
+	var rolled fsrs.Card
+	post(t, h, "/v1/rollback", map[string]any{"Card": next.Card, "ReviewLog": next.ReviewLog}, &rolled)
+	if rolled.State != fsrs.New || !rolled.Due.Equal(card.Due) {
+		t.Errorf("rollback: got=%+v", rolled)
+	}
+
+	var forgotten fsrs.SchedulingInfo
+	post(t, h, "/v1/forget", map[string]any{"Card": next.Card, "Now": later, "ResetCount": true}, &forgotten)
+	if forgotten.Card.State != fsrs.New || forgotten.Card.Reps != 0 {
+		t.Errorf("forget: got=%+v", forgotten)
+	}
+
+	var rescheduled fsrs.RescheduleResult
+	reviews := []fsrs.ReviewHistory{{Rating: fsrs.Good, Review: now}}
+	post(t, h, "/v1/reschedule", map[string]any{"Profile": "strict", "Card": card, "Reviews": reviews, "Options": fsrs.RescheduleOptions{Now: later}}, &rescheduled)
+	if len(rescheduled.Collections) != 1 || rescheduled.Collections[0].Card.Stability != want.Card.Stability {
+		t.Errorf("reschedule: got=%+v", rescheduled)
+	}
+
+	var memory fsrs.MemoryState
+	history := fsrs.ReviewEntries{{Rating: fsrs.Good, DeltaT: 0}, {Rating: fsrs.Good, DeltaT: 3}}
+	post(t, h, "/v1/memory-state", map[string]any{"History": history}, &memory)
+	wantMemory, _ := fsrs.NewFSRS(fsrs.DefaultParam()).MemoryState(history, nil)
+	if !reflect.DeepEqual(&memory, wantMemory) {
+		t.Errorf("memory-state: got=%+v want=%+v", memory, wantMemory)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	h := newHandler(nil)
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		path   string
+		body   any
+		status int
+		code   fsrs.ErrorCode
+	}{
+		{"unknown profile", "/v1/next", map[string]any{"Profile": "missing"}, http.StatusNotFound, 0},
+		{"invalid parameters", "/v1/next", map[string]any{"Parameters": map[string]any{"RequestRetention": 2}}, http.StatusBadRequest, fsrs.ErrCodeInvalidRetention},
+		{"invalid rating", "/v1/next", map[string]any{"Card": fsrs.NewCard(now), "Now": now, "Rating": 9}, http.StatusBadRequest, fsrs.ErrCodeInvalidInput},
+		{"invalid timezone", "/v1/repeat", map[string]any{"Timezone": "Nowhere/Land"}, http.StatusBadRequest, 0},
+	}
+	for _, tc := range cases {
+		var resp errorResponse
+		if code := post(t, h, tc.path, tc.body, &resp); code != tc.status || resp.Code != tc.code || resp.Message == "" {
+			t.Errorf("%s: status=%d resp=%+v, want status=%d code=%d", tc.name, code, resp, tc.status, tc.code)
+		}
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte(`{"relaxed": {"RequestRetention": 0.8}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := loadProfiles(path)
+	if err != nil {
+		t.Fatalf("loadProfiles returned error: %v", err)
+	}
+	if p := profiles["relaxed"]; p.RequestRetention != 0.8 || p.W != fsrs.DefaultWeights() {
+		t.Errorf("expected the profile on top of the defaults, got=%+v", p)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"bad": {"MaximumInterval": -1}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadProfiles(path); err == nil {
+		t.Error("expected an invalid profile to be rejected")
+	}
+}
+
+func TestOptimalRetention(t *testing.T) {
+	h := newHandler(nil)
+	config := map[string]any{"DeckSize": 50, "LearnSpan": 30, "MaxNewPerDay": 10}
+	var resp optimalRetentionResponse
+	if code := post(t, h, "/v1/optimal-retention", map[string]any{"Config": config}, &resp); code != http.StatusOK {
+		t.Fatalf("optimal-retention: status %d", code)
+	}
+	if resp.OptimalRetention < 0.7 || resp.OptimalRetention > 0.95 {
+		t.Errorf("expected a retention in [0.7, 0.95], got=%v", resp.OptimalRetention)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data, _ := json.Marshal(map[string]any{"Config": config})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/optimal-retention", bytes.NewReader(data)).WithContext(ctx))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected a cancelled request to stop with 503, got=%d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -16,6 +16,12 @@ const (
 	ErrCodeInvalidRetention
 	ErrCodeInvalidMaxInterval
 	ErrCodeInvalidSteps
+	ErrCodeNotEnoughData
+	ErrCodeInvalidEasyDays
+	ErrCodeInvalidDayStartHour
+	ErrCodeCardSuspended
+	ErrCodeCardNotFound
+	ErrCodeParametersNotFound
 )
 
 // Error represents a structured FSRS error with a machine-readable code
@@ -112,4 +118,45 @@ var (
 		Code:    ErrCodeInvalidSteps,
 		Message: "fsrs: invalid steps: must be finite and >= 0",
 	}
+
+	// ErrInvalidEasyDays is returned by Validate when EasyDays does not hold
+	// exactly 7 values in [0, 1].
+	ErrInvalidEasyDays = &Error{
+		Code:    ErrCodeInvalidEasyDays,
+		Message: "fsrs: invalid EasyDays: must be empty or 7 values in [0, 1]",
+	}
+
+	// ErrInvalidDayStartHour is returned by Validate when DayStartHour is
+	// outside [0, 23].
+	ErrInvalidDayStartHour = &Error{
+		Code:    ErrCodeInvalidDayStartHour,
+		Message: "fsrs: invalid DayStartHour: must be in [0, 23]",
+	}
+
+	// ErrCardSuspended is returned by Next and Repeat for a suspended card.
+	ErrCardSuspended = &Error{
+		Code:    ErrCodeCardSuspended,
+		Message: "fsrs: card is suspended",
+	}
+
+	// ErrCardNotFound is returned by a Store when no card has the requested ID.
+	ErrCardNotFound = &Error{
+		Code:    ErrCodeCardNotFound,
+		Message: "fsrs: card not found",
+	}
+
+	// ErrParametersNotFound is returned by SQLStore.LoadParameters when no
+	// parameters are stored under the requested profile.
+	ErrParametersNotFound = &Error{
+		Code:    ErrCodeParametersNotFound,
+		Message: "fsrs: parameters not found",
+	}
+
+	// ErrNotEnoughData is returned by Optimize, PretrainInitialStability and
+	// Evaluate when the review data contains no usable samples, and by
+	// OptimalRetention when the simulated deck is empty.
+	ErrNotEnoughData = &Error{
+		Code:    ErrCodeNotEnoughData,
+		Message: "fsrs: not enough review data",
+	}
 )
@@ -0,0 +1,136 @@
+package fsrs
+
+import (
+	"math"
+	"sort"
+)
+
+// EvaluationMetrics summarizes how well a parameter set predicts recall on a
+// set of review histories. See [FSRS.Evaluate].
+type EvaluationMetrics struct {
+	LogLoss  float64 `json:"LogLoss"`
+	RMSEBins float64 `json:"RMSEBins"`
+	AUC      float64 `json:"AUC"`
+	Count    int     `json:"Count"`
+}
+
+// Evaluate replays each history through [FSRS.HistoricalMemoryStates] and
+// predicts recall with [Parameters.ForgettingCurve] at every review after the
+// first with DeltaT > 0, which is counted as recalled unless rated Again.
+//
+// LogLoss is the mean binary cross-entropy. RMSEBins is the weighted RMSE of
+// the FSRS benchmark, computed over bins of elapsed days, review number and
+// lapse count. AUC is the ROC AUC of the predictions and is NaN when every
+// review has the same outcome. Empty histories are skipped; returns
+// ErrNotEnoughData when no review can be predicted.
+func (f *FSRS) Evaluate(histories []ReviewEntries) (EvaluationMetrics, error) {
+	type prediction struct {
+		r        float64
+		recalled bool
+	}
+	type binKey struct {
+		deltaT, i, lapses float64
+	}
+	type bin struct {
+		predicted, observed, count float64
+	}
+
+	var preds []prediction
+	bins := map[binKey]*bin{}
+	logLoss := 0.0
+	for _, h := range histories {
+		if len(h) == 0 {
+			continue
+		}
+		states, err := f.HistoricalMemoryStates(h, nil)
+		if err != nil {
+			return EvaluationMetrics{}, err
+		}
+		lapses := 0
+		for i := 1; i < len(h); i++ {
+			if h[i].DeltaT <= 0 {
+				continue
+			}
+			r := clamp(f.ForgettingCurve(h[i].DeltaT, states[i-1].Stability), minProbability, 1-minProbability)
+			recalled := h[i].Rating > Again
+			y := 0.0
+			if recalled {
+				y = 1
+				logLoss -= math.Log(r)
+			} else {
+				logLoss -= math.Log(1 - r)
+			}
+			preds = append(preds, prediction{r: r, recalled: recalled})
+
+			key := binKey{
+				deltaT: benchmarkBin(h[i].DeltaT, 2.48, 3.62, 2),
+				i:      benchmarkBin(float64(i+1), 1.99, 1.89, 0),
+				lapses: benchmarkBin(float64(lapses), 1.65, 1.73, 0),
+			}
+			b := bins[key]
+			if b == nil {
+				b = &bin{}
+				bins[key] = b
+			}
+			b.predicted += r
+			b.observed += y
+			b.count++
+
+			if !recalled {
+				lapses++
+			}
+		}
+	}
+	if len(preds) == 0 {
+		return EvaluationMetrics{}, ErrNotEnoughData
+	}
+
+	sq, weight := 0.0, 0.0
+	for _, b := range bins {
+		diff := b.observed/b.count - b.predicted/b.count
+		sq += b.count * diff * diff
+		weight += b.count
+	}
+
+	sort.Slice(preds, func(i, j int) bool { return preds[i].r < preds[j].r })
+	var positives, negatives, rankSum float64
+	for i := 0; i < len(preds); {
+		j := i
+		for j < len(preds) && preds[j].r == preds[i].r {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if preds[k].recalled {
+				positives++
+				rankSum += rank
+			} else {
+				negatives++
+			}
+		}
+		i = j
+	}
+	auc := math.NaN()
+	if positives > 0 && negatives > 0 {
+		auc = (rankSum - positives*(positives+1)/2) / (positives * negatives)
+	}
+
+	return EvaluationMetrics{
+		LogLoss:  logLoss / float64(len(preds)),
+		RMSEBins: math.Sqrt(sq / weight),
+		AUC:      auc,
+		Count:    len(preds),
+	}, nil
+}
+
+// benchmarkBin maps x onto the geometric bins used by the FSRS benchmark:
+// scale * base^floor(log_base(x)), rounded to the given number of decimals.
+// Zero maps to its own bin.
+func benchmarkBin(x, scale, base float64, decimals int) float64 {
+	if x <= 0 {
+		return 0
+	}
+	v := scale * math.Pow(base, math.Floor(math.Log(x)/math.Log(base)))
+	ratio := math.Pow(10, float64(decimals))
+	return math.Round(v*ratio) / ratio
+}
@@ -0,0 +1,87 @@
+package fsrs
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	f := NewFSRS(DefaultParam())
+	histories := syntheticHistories(DefaultWeights(), 500, 6, "evaluate")
+
+	metrics, err := f.Evaluate(histories)
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+
+	p := DefaultParam()
+	loss, n := p.logLoss(histories)
+	if metrics.Count != n {
+		t.Errorf("expected Count=%d, got=%d", n, metrics.Count)
+	}
+	if math.Abs(metrics.LogLoss-loss/float64(n)) > 1e-9 {
+		t.Errorf("expected LogLoss=%v, got=%v", loss/float64(n), metrics.LogLoss)
+	}
+	if metrics.RMSEBins <= 0 || metrics.RMSEBins > 0.1 {
+		t.Errorf("expected small RMSEBins on data generated by the same weights, got=%v", metrics.RMSEBins)
+	}
+	if metrics.AUC <= 0.5 || metrics.AUC > 1 {
+		t.Errorf("expected AUC in (0.5, 1], got=%v", metrics.AUC)
+	}
+}
+
+func TestEvaluateAUC(t *testing.T) {
+	f := NewFSRS(DefaultParam())
+	// Longer gaps give lower predicted recall, so forgetting only at the
+	// longest gaps separates the classes perfectly.
+	histories := []ReviewEntries{
+		{{Rating: Good, DeltaT: 0}, {Rating: Good, DeltaT: 1}},
+		{{Rating: Good, DeltaT: 0}, {Rating: Good, DeltaT: 2}},
+		{{Rating: Good, DeltaT: 0}, {Rating: Again, DeltaT: 30}},
+		{{Rating: Good, DeltaT: 0}, {Rating: Again, DeltaT: 60}},
+	}
+	metrics, err := f.Evaluate(histories)
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if metrics.AUC != 1 {
+		t.Errorf("expected AUC=1, got=%v", metrics.AUC)
+	}
+
+	metrics, err = f.Evaluate(histories[:2])
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if !math.IsNaN(metrics.AUC) {
+		t.Errorf("expected NaN AUC with a single class, got=%v", metrics.AUC)
+	}
+}
+
+func TestEvaluateNotEnoughData(t *testing.T) {
+	f := NewFSRS(DefaultParam())
+	_, err := f.Evaluate([]ReviewEntries{{}, {{Rating: Good, DeltaT: 0}}})
+	if !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("expected ErrNotEnoughData, got=%v", err)
+	}
+}
+
+func TestBenchmarkBin(t *testing.T) {
+	tests := []struct {
+		x, scale, base float64
+		decimals       int
+		want           float64
+	}{
+		{0, 1.65, 1.73, 0, 0},
+		{1, 2.48, 3.62, 2, 2.48},
+		{3, 2.48, 3.62, 2, 2.48},
+		{4, 2.48, 3.62, 2, 8.98},
+		{2, 1.99, 1.89, 0, 4},
+		{1, 1.65, 1.73, 0, 2},
+	}
+	for _, tt := range tests {
+		if got := benchmarkBin(tt.x, tt.scale, tt.base, tt.decimals); got != tt.want {
+			t.Errorf("benchmarkBin(%v, %v, %v, %d) = %v, want %v", tt.x, tt.scale, tt.base, tt.decimals, got, tt.want)
+		}
+	}
+}
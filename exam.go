@@ -0,0 +1,56 @@
+package fsrs
+
+import (
+	"math"
+	"time"
+)
+
+// prepareExam selects the exam date that applies to card, reviewed at now,
+// and raises RequestRetention as the exam approaches when ExamRampDays is
+// set. It is called on the Scheduler's own copy of the parameters.
+func (p *Parameters) prepareExam(card Card, now time.Time) {
+	p.examDate = card.ExamDate
+	if p.examDate.IsZero() {
+		p.examDate = p.ExamDate
+	}
+	days, ok := p.daysToExam(now)
+	if !ok || p.ExamRampDays <= 0 || days >= p.ExamRampDays {
+		return
+	}
+	if target := p.examRetention(); target > p.RequestRetention {
+		p.RequestRetention += (target - p.RequestRetention) * (1 - days/p.ExamRampDays)
+	}
+}
+
+// daysToExam returns the review days from now to the exam, and false when
+// there is no exam or it is not after the review day of now.
+func (p *Parameters) daysToExam(now time.Time) (float64, bool) {
+	if p.examDate.IsZero() || !p.reviewDay(p.examDate).After(p.reviewDay(now)) {
+		return 0, false
+	}
+	return float64(p.daysBetween(now, p.examDate)), true
+}
+
+func (p *Parameters) examRetention() float64 {
+	if p.ExamRetention > 0 {
+		return p.ExamRetention
+	}
+	return p.RequestRetention
+}
+
+// capForExam shortens an interval in days that would skip past the exam
+// when the forgetting curve predicts that a card with the given stability
+// would fall below the exam retention by then. The card is then due the day
+// before the exam, or on the exam day itself when the exam is tomorrow. It
+// runs after the grades' intervals are ordered, so that no grade is pushed
+// past the exam again, and may therefore leave several grades equal.
+func (p *Parameters) capForExam(interval, stability float64) float64 {
+	days, ok := p.daysToExam(p.reviewTime)
+	if !ok || interval < days {
+		return interval
+	}
+	if p.ForgettingCurve(days, stability) >= p.examRetention() {
+		return interval
+	}
+	return math.Max(days-1, 1)
+}
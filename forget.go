@@ -3,14 +3,17 @@ package fsrs
 import "time"
 
 // Forget resets a card to the New state. When resetCount is false, the card's
-// Reps and Lapses counters are preserved; otherwise they are zeroed.
+// Reps and Lapses counters and its Leech flag are preserved; otherwise they
+// are zeroed. Suspended is always kept, so a suspended leech stays suspended
+// until the flag is cleared explicitly.
 // The returned SchedulingInfo contains the reset card and a Manual review log
 // that captures the card's pre-forget state (State, Due, Stability, Difficulty,
-// ScheduledDays, RemainingSteps). The card's LastReview is preserved.
+// ScheduledDays, RemainingSteps). The card's LastReview is preserved, as are
+// its ID, NoteID, ExamDate and DesiredRetention.
 func (f *FSRS) Forget(card Card, now time.Time, resetCount bool) SchedulingInfo {
 	scheduledDays := uint64(0)
 	if card.State != New {
-		scheduledDays = dateDiffInDays(now, card.Due)
+		scheduledDays = f.daysBetween(now, card.Due)
 	}
 	forgetLog := ReviewLog{
 		Rating:         Manual,
@@ -22,14 +25,30 @@ func (f *FSRS) Forget(card Card, now time.Time, resetCount bool) SchedulingInfo
 		RemainingSteps: card.RemainingSteps,
 		Review:         now,
 	}
-	forgetCard := Card{
-		Due:        now,
-		State:      New,
-		LastReview: card.LastReview,
-	}
+	forgetCard := resetCard(card, now)
+	forgetCard.LastReview = card.LastReview
+	forgetCard.Suspended = card.Suspended
 	if !resetCount {
 		forgetCard.Reps = card.Reps
 		forgetCard.Lapses = card.Lapses
+		forgetCard.Leech = card.Leech
 	}
 	return SchedulingInfo{Card: forgetCard, ReviewLog: forgetLog}
 }
+
+// resetCard returns card as a New card due at due. Only the scheduling
+// state is cleared; ID, NoteID, ExamDate and DesiredRetention are kept.
+func resetCard(card Card, due time.Time) Card {
+	card.Due = due
+	card.Stability = 0
+	card.Difficulty = 0
+	card.ScheduledDays = 0
+	card.Reps = 0
+	card.Lapses = 0
+	card.State = New
+	card.LastReview = time.Time{}
+	card.RemainingSteps = 0
+	card.Leech = false
+	card.Suspended = false
+	return card
+}
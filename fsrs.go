@@ -10,15 +10,21 @@ import (
 // A zero-value FSRS is not usable; always construct instances with NewFSRS.
 type FSRS struct {
 	Parameters
+	// Clock supplies the current time wherever the API defaults to now.
+	// nil means SystemClock.
+	Clock Clock
 }
 
 // NewFSRS creates a new FSRS instance with the given parameters.
-// All weights are clipped to valid ranges before validation. If validation
-// fails after clipping, all parameters are reset to defaults.
+// All weights are clipped to valid ranges before validation. If the weights,
+// RequestRetention, MaximumInterval or learning steps fail validation after
+// clipping, all parameters are reset to defaults. Invalid scheduling options
+// (EasyDays, DayStartHour, ExamRetention, ExamRampDays) are kept as given and
+// reported by Next and Repeat instead.
 func NewFSRS(param Parameters) *FSRS {
 	clipParameters(&param)
 
-	if param.Validate() != nil {
+	if param.validateModel() != nil {
 		param = DefaultParam()
 	}
 
@@ -29,36 +35,87 @@ func NewFSRS(param Parameters) *FSRS {
 	}
 }
 
+// Now returns the current time according to f.Clock.
+func (f *FSRS) Now() time.Time {
+	if f.Clock == nil {
+		return time.Now()
+	}
+	return f.Clock.Now()
+}
+
+// NewCard returns a new Card due at f.Now().
+func (f *FSRS) NewCard() Card {
+	return NewCard(f.Now())
+}
+
 // Repeat previews the scheduling result for all four ratings (Again, Hard, Good, Easy)
 // without modifying any card state. Returns a RecordLog keyed by Rating.
-// Returns an error if the card or computed results are invalid.
+// Returns an error if the card, computed results or scheduling options (see
+// NewFSRS) are invalid, or ErrCardSuspended if the card is suspended.
 func (f *FSRS) Repeat(card Card, now time.Time) (RecordLog, error) {
+	return f.repeat(card, now, f.scheduler)
+}
+
+// repeat implements Repeat, taking the Scheduler from newScheduler.
+func (f *FSRS) repeat(card Card, now time.Time, newScheduler func(Card, time.Time) *Scheduler) (RecordLog, error) {
+	if err := f.validateOptions(); err != nil {
+		return RecordLog{}, err
+	}
 	if err := validateCard(card, now); err != nil {
 		return RecordLog{}, err
 	}
-	log := f.scheduler(card, now).Preview()
+	log := newScheduler(card, now).Preview()
 	for _, rating := range []Rating{Again, Hard, Good, Easy} {
 		if err := validateResult(log[rating].Card); err != nil {
 			return RecordLog{}, err
 		}
+		log[rating] = f.markLeech(card, log[rating])
 	}
 	return log, nil
 }
 
 // Next applies a single review with the given grade and returns the updated card
-// and its review log. Returns an error if the grade, card, or computed result is invalid.
+// and its review log. Returns an error if the grade, card, computed result or
+// scheduling options are invalid, or ErrCardSuspended if the card is suspended.
 func (f *FSRS) Next(card Card, now time.Time, grade Rating) (SchedulingInfo, error) {
+	return f.next(card, now, grade, f.scheduler)
+}
+
+// next implements Next, taking the Scheduler from newScheduler.
+func (f *FSRS) next(card Card, now time.Time, grade Rating, newScheduler func(Card, time.Time) *Scheduler) (SchedulingInfo, error) {
 	if err := validateRating(grade); err != nil {
 		return SchedulingInfo{}, err
 	}
+	if err := f.validateOptions(); err != nil {
+		return SchedulingInfo{}, err
+	}
 	if err := validateCard(card, now); err != nil {
 		return SchedulingInfo{}, err
 	}
-	info := f.scheduler(card, now).Review(grade)
+	info := newScheduler(card, now).Review(grade)
 	if err := validateResult(info.Card); err != nil {
 		return SchedulingInfo{}, err
 	}
-	return info, nil
+	return f.markLeech(card, info), nil
+}
+
+// NextWithSiblings is like Next, but when fuzz is enabled it also avoids
+// placing the card's new due date on a review day on which one of its
+// siblings is due. Siblings are the cards in siblings that share the card's
+// non-zero NoteID, excluding the card itself (same non-zero ID) and New or
+// suspended cards.
+func (f *FSRS) NextWithSiblings(card Card, now time.Time, grade Rating, siblings []Card) (SchedulingInfo, error) {
+	g := *f
+	g.siblingDues = nil
+	if card.NoteID != 0 {
+		for _, s := range siblings {
+			if s.NoteID != card.NoteID || (s.ID != 0 && s.ID == card.ID) || s.State == New || s.Suspended {
+				continue
+			}
+			g.siblingDues = append(g.siblingDues, s.Due)
+		}
+	}
+	return g.Next(card, now, grade)
 }
 
 // Retrievability returns the current retrievability (probability of recall) for
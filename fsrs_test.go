@@ -1,11 +1,15 @@
 package fsrs
 
 import (
+	"encoding/json"
 	"errors"
+	"hash/fnv"
 	"math"
+	"math/rand/v2"
 	"reflect"
 	"testing"
 	"time"
+	_ "time/tzdata"
 )
 
 func roundFloat(val float64, precision uint) float64 {
@@ -4997,3 +5001,829 @@ func TestDefaultRelearningSteps(t *testing.T) {
 		t.Error("mutating returned slice affected future calls")
 	}
 }
+
+func TestLoadBalancer(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	card := Card{
+		Due:        now,
+		Stability:  30,
+		Difficulty: 5,
+		State:      Review,
+		Reps:       5,
+		LastReview: now.Add(-30 * 24 * time.Hour),
+	}
+
+	p := DefaultParam()
+	plain := NewFSRS(p)
+	info, err := plain.Next(card, now, Good)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	minInterval, maxInterval := getFuzzRange(float64(info.Card.ScheduledDays), 30, p.MaximumInterval)
+	if minInterval == maxInterval {
+		t.Fatalf("expected a fuzz range with several days, got [%d, %d]", minInterval, maxInterval)
+	}
+
+	for target := minInterval; target <= maxInterval; target++ {
+		targetDay := now.AddDate(0, 0, target).Format(time.DateOnly)
+		var seen []string
+		p.EnableFuzz = true
+		p.LoadBalancer = LoadBalancerFunc(func(due time.Time) int {
+			seen = append(seen, due.Format(time.DateOnly))
+			if due.Format(time.DateOnly) == targetDay {
+				return 0
+			}
+			return 1000
+		})
+		info, err := NewFSRS(p).Next(card, now, Good)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Card.ScheduledDays != uint64(target) {
+			t.Errorf("expected the only empty day %d to be chosen, got=%d", target, info.Card.ScheduledDays)
+		}
+		if got := info.Card.Due.Format(time.DateOnly); got != targetDay {
+			t.Errorf("expected due on %s, got=%s", targetDay, got)
+		}
+		if len(seen) == 0 {
+			t.Errorf("expected the load balancer to be consulted")
+		}
+	}
+}
+
+func TestEasyDays(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	card := Card{
+		Due:        now,
+		Stability:  30,
+		Difficulty: 5,
+		State:      Review,
+		Reps:       5,
+		LastReview: now.Add(-30 * 24 * time.Hour),
+	}
+	p := DefaultParam()
+	info, err := NewFSRS(p).Next(card, now, Good)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	minInterval, maxInterval := getFuzzRange(float64(info.Card.ScheduledDays), 30, p.MaximumInterval)
+	if maxInterval-minInterval < 6 {
+		t.Fatalf("expected a fuzz range covering a week, got [%d, %d]", minInterval, maxInterval)
+	}
+
+	p.EnableFuzz = true
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		p.EasyDays = make([]float64, 7)
+		p.EasyDays[weekday] = 1
+		info, err := NewFSRS(p).Next(card, now, Good)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := info.Card.Due.Weekday(); got != weekday {
+			t.Errorf("expected due on %v, got=%v", weekday, got)
+		}
+	}
+
+	t.Run("all days avoided falls back to the fuzz range", func(t *testing.T) {
+		p.EasyDays = make([]float64, 7)
+		info, err := NewFSRS(p).Next(card, now, Good)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := int(info.Card.ScheduledDays); got < minInterval || got > maxInterval {
+			t.Errorf("expected interval in [%d, %d], got=%d", minInterval, maxInterval, got)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for _, days := range [][]float64{{1, 1}, {1, 1, 1, 1, 1, 1, 1.5}, {1, 1, 1, 1, 1, 1, math.NaN()}} {
+			p := DefaultParam()
+			p.EasyDays = days
+			if err := p.Validate(); !errors.Is(err, ErrInvalidEasyDays) {
+				t.Errorf("EasyDays=%v: expected ErrInvalidEasyDays, got=%v", days, err)
+			}
+		}
+		p := DefaultParam()
+		p.EasyDays = []float64{0, 1, 1, 1, 1, 1, 0.5}
+		if err := p.Validate(); err != nil {
+			t.Errorf("expected valid EasyDays, got=%v", err)
+		}
+	})
+
+	t.Run("invalid options keep trained weights", func(t *testing.T) {
+		p := DefaultParam()
+		p.W[0] = 0.5
+		p.W[20] = 0.3
+		p.EasyDays = []float64{1, 1, 1, 1, 1, 1}
+		f := NewFSRS(p)
+		if f.W != p.W || f.Decay != -0.3 {
+			t.Errorf("expected the weights to survive an invalid option, got W=%v Decay=%v", f.W, f.Decay)
+		}
+		if _, err := f.Next(card, now, Good); !errors.Is(err, ErrInvalidEasyDays) {
+			t.Errorf("expected Next to report ErrInvalidEasyDays, got=%v", err)
+		}
+		if _, err := f.Repeat(card, now); !errors.Is(err, ErrInvalidEasyDays) {
+			t.Errorf("expected Repeat to report ErrInvalidEasyDays, got=%v", err)
+		}
+	})
+}
+
+func TestDayBoundary(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+
+	t.Run("daysBetween", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			loc       *time.Location
+			startHour int
+			last, now time.Time
+			want      uint64
+		}{
+			{"UTC default splits Tokyo morning", nil, 0, time.Date(2024, 5, 10, 8, 0, 0, 0, tokyo), time.Date(2024, 5, 10, 10, 0, 0, 0, tokyo), 1},
+			{"Tokyo location keeps same day", tokyo, 0, time.Date(2024, 5, 10, 8, 0, 0, 0, tokyo), time.Date(2024, 5, 10, 10, 0, 0, 0, tokyo), 0},
+			{"rollover hour counts early morning as previous day", tokyo, 4, time.Date(2024, 5, 10, 23, 0, 0, 0, tokyo), time.Date(2024, 5, 11, 3, 0, 0, 0, tokyo), 0},
+			{"rollover hour crossed", tokyo, 4, time.Date(2024, 5, 10, 23, 0, 0, 0, tokyo), time.Date(2024, 5, 11, 5, 0, 0, 0, tokyo), 1},
+			{"reversed returns 0", tokyo, 4, time.Date(2024, 5, 12, 5, 0, 0, 0, tokyo), time.Date(2024, 5, 10, 5, 0, 0, 0, tokyo), 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				p := DefaultParam()
+				p.Location = tt.loc
+				p.DayStartHour = tt.startHour
+				if got := p.daysBetween(tt.last, tt.now); got != tt.want {
+					t.Errorf("daysBetween(%v, %v) = %d, want %d", tt.last, tt.now, got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("scheduler elapsed days", func(t *testing.T) {
+		p := DefaultParam()
+		p.Location = tokyo
+		f := NewFSRS(p)
+		first := time.Date(2024, 5, 10, 8, 0, 0, 0, tokyo)
+		info, err := f.Next(NewCard(first), first, Good)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second := time.Date(2024, 5, 10, 10, 0, 0, 0, tokyo)
+		s := p.scheduler(info.Card, second)
+		if got := s.elapsedDays(); got != 0 {
+			t.Errorf("expected 0 elapsed days within the same Tokyo day, got=%v", got)
+		}
+	})
+
+	t.Run("forget uses review days", func(t *testing.T) {
+		p := DefaultParam()
+		p.Location = tokyo
+		p.DayStartHour = 4
+		f := NewFSRS(p)
+		now := time.Date(2024, 5, 10, 22, 0, 0, 0, tokyo)
+		card := Card{Due: time.Date(2024, 5, 11, 3, 0, 0, 0, tokyo), State: Review, Stability: 3, Difficulty: 5}
+		if got := f.Forget(card, now, false).ReviewLog.ScheduledDays; got != 0 {
+			t.Errorf("expected 0 scheduled days before the rollover hour, got=%d", got)
+		}
+	})
+
+	t.Run("due dates across daylight saving", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := DefaultParam()
+		p.Location = newYork
+		p.DayStartHour = 4
+		now := time.Date(2024, 11, 2, 4, 30, 0, 0, newYork)
+		if got, want := p.addDays(now, 1), time.Date(2024, 11, 3, 4, 30, 0, 0, newYork); !got.Equal(want) {
+			t.Errorf("expected one review day later at the same local time, got=%v want=%v", got, want)
+		}
+
+		card := Card{Due: now, State: Review, Stability: 1, Difficulty: 5, ScheduledDays: 1, Reps: 3, LastReview: now.AddDate(0, 0, -1)}
+		for _, strategy := range []StrategyFunc{BasicStrategy, LongTermStrategy} {
+			p.Strategy = strategy
+			log, err := NewFSRS(p).Repeat(card, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, grade := range []Rating{Hard, Good, Easy} {
+				next := log[grade].Card
+				if got := p.daysBetween(now, next.Due); got != next.ScheduledDays {
+					t.Errorf("%v: expected the due date %d review days ahead, got=%d (due %v)", grade, next.ScheduledDays, got, next.Due)
+				}
+			}
+		}
+	})
+
+	t.Run("location survives JSON", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := DefaultParam()
+		p.Location = newYork
+		p.DayStartHour = 4
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Parameters
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Location == nil || got.Location.String() != "America/New_York" || got.DayStartHour != 4 {
+			t.Errorf("expected the time zone to round-trip, got Location=%v in %s", got.Location, data)
+		}
+
+		kept := DefaultParam()
+		kept.Location = newYork
+		if err := json.Unmarshal([]byte(`{"DayStartHour": 5}`), &kept); err != nil || kept.Location != newYork {
+			t.Errorf("expected a missing Location to be left alone, got=%v err=%v", kept.Location, err)
+		}
+		if err := json.Unmarshal([]byte(`{"Location": "Mars/Olympus"}`), &kept); err == nil {
+			t.Error("expected an unknown Location to fail")
+		}
+		p.Location = tokyo
+		if _, err := json.Marshal(p); err == nil {
+			t.Error("expected a fixed zone to fail to marshal")
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for _, h := range []int{-1, 24} {
+			p := DefaultParam()
+			p.DayStartHour = h
+			if err := p.Validate(); !errors.Is(err, ErrInvalidDayStartHour) {
+				t.Errorf("DayStartHour=%d: expected ErrInvalidDayStartHour, got=%v", h, err)
+			}
+		}
+	})
+}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	f := NewFSRS(DefaultParam())
+	f.Clock = clock
+
+	card := f.NewCard()
+	if !card.Due.Equal(start) {
+		t.Errorf("expected NewCard due at %v, got=%v", start, card.Due)
+	}
+
+	clock.Advance(48 * time.Hour)
+	if got := f.Now(); !got.Equal(start.Add(48 * time.Hour)) {
+		t.Errorf("expected advanced clock, got=%v", got)
+	}
+
+	reviews := []ReviewHistory{{Rating: Good, Review: start}}
+	result, err := f.Reschedule(card, reviews, RescheduleOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RescheduleItem == nil {
+		t.Fatalf("expected a reschedule item")
+	}
+	if got := result.RescheduleItem.ReviewLog.Review; !got.Equal(clock.Now()) {
+		t.Errorf("expected reschedule review at clock time %v, got=%v", clock.Now(), got)
+	}
+
+	later := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock.Set(later)
+	a, b := Alea(clock), Alea(later.UnixNano())
+	if a() != b() {
+		t.Errorf("expected Alea seeded from a Clock to match its UnixNano seed")
+	}
+}
+
+func TestLeech(t *testing.T) {
+	now := time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)
+	p := DefaultParam()
+	p.LeechThreshold = 3
+	p.LeechRepeat = 2
+	p.LeechSuspend = true
+	f := NewFSRS(p)
+
+	t.Run("isLeech", func(t *testing.T) {
+		want := map[uint64]bool{0: false, 2: false, 3: true, 4: false, 5: true, 6: false, 7: true}
+		for lapses, expected := range want {
+			if got := f.isLeech(lapses); got != expected {
+				t.Errorf("isLeech(%d) = %v, want %v", lapses, got, expected)
+			}
+		}
+		disabled := DefaultParam()
+		if disabled.isLeech(100) {
+			t.Errorf("expected leech detection disabled by default")
+		}
+	})
+
+	card := Card{Due: now, State: Review, Stability: 10, Difficulty: 6, Reps: 8, Lapses: 2, LastReview: now.Add(-10 * 24 * time.Hour)}
+
+	t.Run("lapse reaching threshold suspends", func(t *testing.T) {
+		info, err := f.Next(card, now, Again)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !info.ReviewLog.Leech || !info.Card.Leech || !info.Card.Suspended {
+			t.Errorf("expected leech log and suspended card, got log=%v card=%+v", info.ReviewLog.Leech, info.Card)
+		}
+
+		_, err = f.Next(info.Card, info.Card.Due, Good)
+		if !errors.Is(err, ErrCardSuspended) {
+			t.Errorf("expected ErrCardSuspended, got=%v", err)
+		}
+		_, err = f.Repeat(info.Card, info.Card.Due)
+		if !errors.Is(err, ErrCardSuspended) {
+			t.Errorf("expected ErrCardSuspended from Repeat, got=%v", err)
+		}
+
+		rolled, err := f.Rollback(info.Card, info.ReviewLog)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rolled.Suspended || rolled.Leech || rolled.Lapses != 2 {
+			t.Errorf("expected rollback to lift the suspension, got=%+v", rolled)
+		}
+	})
+
+	t.Run("only the lapsing rating is flagged", func(t *testing.T) {
+		log, err := f.Repeat(card, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !log[Again].ReviewLog.Leech {
+			t.Errorf("expected Again to be flagged")
+		}
+		for _, r := range []Rating{Hard, Good, Easy} {
+			if log[r].ReviewLog.Leech || log[r].Card.Suspended {
+				t.Errorf("expected %v not to be flagged", r)
+			}
+		}
+	})
+
+	t.Run("tag only", func(t *testing.T) {
+		p := p
+		p.LeechSuspend = false
+		info, err := NewFSRS(p).Next(card, now, Again)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !info.Card.Leech || info.Card.Suspended {
+			t.Errorf("expected a tagged but unsuspended card, got=%+v", info.Card)
+		}
+	})
+
+	t.Run("forget keeps the suspension", func(t *testing.T) {
+		leech := card
+		leech.Lapses, leech.Leech, leech.Suspended = 3, true, true
+		kept := f.Forget(leech, now, false).Card
+		if !kept.Leech || !kept.Suspended || kept.Lapses != 3 {
+			t.Errorf("expected Forget without resetCount to keep the leech flags, got=%+v", kept)
+		}
+		reset := f.Forget(leech, now, true).Card
+		if reset.Leech || !reset.Suspended || reset.Lapses != 0 {
+			t.Errorf("expected resetCount to clear Leech but keep Suspended, got=%+v", reset)
+		}
+	})
+
+	t.Run("replayed forget keeps the suspension", func(t *testing.T) {
+		p := p
+		p.LeechThreshold = 1
+		later := now.AddDate(0, 0, 10)
+		result, err := NewFSRS(p).Reschedule(NewCard(now), []ReviewHistory{
+			{Rating: Manual, State: StatePtr(Review), Review: now, Due: later, Stability: 10, Difficulty: 6},
+			{Rating: Again, Review: later},
+			{Rating: Manual, State: StatePtr(New), Review: later.Add(time.Hour)},
+		}, RescheduleOptions{Now: later.Add(time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		lapsed, forgotten := result.Collections[1].Card, result.Collections[2].Card
+		if !lapsed.Suspended {
+			t.Fatalf("expected the lapse to suspend the card, got=%+v", lapsed)
+		}
+		if !forgotten.Leech || !forgotten.Suspended {
+			t.Errorf("expected a replayed forget to keep the leech flags, got=%+v", forgotten)
+		}
+	})
+
+	t.Run("queue skips suspended cards", func(t *testing.T) {
+		suspended := card
+		suspended.Suspended = true
+		items := NewQueue(f, DefaultQueueOptions()).Build([]Card{suspended, card}, now)
+		if len(items) != 1 || items[0].Index != 1 {
+			t.Errorf("expected only the unsuspended card, got=%+v", items)
+		}
+	})
+}
+
+func TestNextWithSiblings(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	card := Card{
+		ID:         1,
+		NoteID:     42,
+		Due:        now,
+		Stability:  30,
+		Difficulty: 5,
+		State:      Review,
+		Reps:       5,
+		LastReview: now.Add(-30 * 24 * time.Hour),
+	}
+	p := DefaultParam()
+	p.EnableFuzz = true
+	f := NewFSRS(p)
+	fuzzed, err := f.Next(card, now, Good)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sibling := Card{ID: 2, NoteID: 42, State: Review, Due: fuzzed.Card.Due, Stability: 10, Difficulty: 5}
+	stranger := Card{ID: 3, NoteID: 7, State: Review, Due: fuzzed.Card.Due, Stability: 10, Difficulty: 5}
+	self := card
+	self.Due = fuzzed.Card.Due
+
+	info, err := f.NextWithSiblings(card, now, Good, []Card{stranger, self})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Card.Due.Equal(fuzzed.Card.Due) {
+		t.Errorf("expected non-siblings to be ignored, got due=%v want=%v", info.Card.Due, fuzzed.Card.Due)
+	}
+
+	info, err = f.NextWithSiblings(card, now, Good, []Card{sibling, stranger})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.reviewDay(info.Card.Due).Equal(p.reviewDay(sibling.Due)) {
+		t.Errorf("expected due date away from the sibling's day %v, got=%v", sibling.Due, info.Card.Due)
+	}
+	base, err := NewFSRS(DefaultParam()).Next(card, now, Good)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	minInterval, maxInterval := getFuzzRange(float64(base.Card.ScheduledDays), 30, p.MaximumInterval)
+	if got := int(info.Card.ScheduledDays); got < minInterval || got > maxInterval {
+		t.Errorf("expected interval near the fuzz range [%d, %d], got=%d", minInterval, maxInterval, got)
+	}
+}
+
+func TestCardIdentitySurvivesResets(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	exam := now.AddDate(0, 2, 0)
+	p := DefaultParam()
+	p.EnableFuzz = true
+	p.SeedStrategy = CardIDSeedStrategy
+	f := NewFSRS(p)
+
+	card := Card{ID: 9, NoteID: 42, Due: now, ExamDate: exam}
+	first, err := f.Next(card, now, Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := f.Next(first.Card, first.Card.Due, Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forgotten := f.Forget(second.Card, second.Card.Due, true).Card
+	if forgotten.ID != 9 || forgotten.NoteID != 42 || !forgotten.ExamDate.Equal(exam) {
+		t.Errorf("expected Forget to keep the card's identity, got=%+v", forgotten)
+	}
+
+	result, err := f.Reschedule(second.Card, []ReviewHistory{
+		{Rating: Good, Review: now},
+		{Rating: Good, Review: first.Card.Due},
+		{Rating: Manual, State: StatePtr(New), Review: second.Card.Due},
+	}, RescheduleOptions{Now: second.Card.Due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range result.Collections {
+		if item.Card.ID != 9 || item.Card.NoteID != 42 || !item.Card.ExamDate.Equal(exam) {
+			t.Errorf("review %d: expected Reschedule to keep the card's identity, got=%+v", i, item.Card)
+		}
+	}
+	if !result.Collections[1].Card.Due.Equal(second.Card.Due) {
+		t.Errorf("expected the replay to fuzz like live reviews, got=%v want=%v", result.Collections[1].Card.Due, second.Card.Due)
+	}
+}
+
+// fixedStepStrategy wraps BasicStrategy and shows learning cards rated Good
+// again after five minutes instead of following the learning steps.
+type fixedStepStrategy struct {
+	SchedulerStrategy
+	s *Scheduler
+}
+
+func (fs fixedStepStrategy) LearningState(grade Rating) SchedulingInfo {
+	item := fs.SchedulerStrategy.LearningState(grade)
+	if grade == Good && fs.s.Last().RemainingSteps > 0 {
+		item.Card.State = Learning
+		item.Card.Due = fs.s.Now().Add(5 * time.Minute)
+		item.Card.ScheduledDays = 0
+	}
+	return item
+}
+
+func TestSchedulerStrategy(t *testing.T) {
+	p := DefaultParam()
+	p.LearningSteps = []float64{1, 10, 60}
+	p.Strategy = func(s *Scheduler) SchedulerStrategy {
+		return fixedStepStrategy{SchedulerStrategy: BasicStrategy(s), s: s}
+	}
+	f := NewFSRS(p)
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	first, err := f.Next(NewCard(now), now, Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := f.Next(first.Card, first.Card.Due, Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := second.Card.Due.Sub(first.Card.Due); got != 5*time.Minute {
+		t.Errorf("expected the custom strategy to schedule 5 minutes, got=%v", got)
+	}
+	preview, err := f.Repeat(first.Card, first.Card.Due)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(preview[Good], second) {
+		t.Errorf("expected Repeat to use the strategy, got=%+v", preview[Good])
+	}
+
+	result, err := f.Reschedule(NewCard(now), []ReviewHistory{
+		{Rating: Good, Review: now},
+		{Rating: Good, Review: first.Card.Due},
+	}, RescheduleOptions{Now: first.Card.Due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Collections[1].Card.Due.Equal(second.Card.Due) {
+		t.Errorf("expected Reschedule to use the strategy, got=%v", result.Collections[1].Card.Due)
+	}
+
+	p.Strategy = LongTermStrategy
+	p.EnableShortTerm = true
+	defaults := DefaultParam()
+	want := defaults.NewLongTermScheduler(NewCard(now), now).Review(Good)
+	got, err := NewFSRS(p).Next(NewCard(now), now, Good)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("expected Strategy to override EnableShortTerm:\n got=%+v\nwant=%+v", got, want)
+	}
+}
+
+func TestSeedStrategyAndFuzzSource(t *testing.T) {
+	p := DefaultParam()
+	p.EnableFuzz = true
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	card := Card{
+		Due: now, Stability: 80, Difficulty: 5, State: Review,
+		LastReview: now.AddDate(0, 0, -80), ScheduledDays: 80, Reps: 5,
+	}
+	dues := func(p Parameters) map[time.Time]bool {
+		f := NewFSRS(p)
+		seen := map[time.Time]bool{}
+		for id := int64(1); id <= 10; id++ {
+			c := card
+			c.ID = id
+			info, err := f.Next(c, now, Good)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen[info.Card.Due] = true
+		}
+		return seen
+	}
+
+	if got := dues(p); len(got) != 1 {
+		t.Errorf("expected the default seed to ignore the card ID, got %d due dates", len(got))
+	}
+	p.SeedStrategy = CardIDSeedStrategy
+	if got := dues(p); len(got) < 2 {
+		t.Errorf("expected the card ID seed to spread due dates, got %d", len(got))
+	}
+
+	var seeds []string
+	p.FuzzSource = func(seed string) PRNG {
+		seeds = append(seeds, seed)
+		h := fnv.New64a()
+		h.Write([]byte(seed))
+		return rand.New(rand.NewPCG(h.Sum64(), 0)).Float64
+	}
+	first, second := dues(p), dues(p)
+	if !reflect.DeepEqual(first, second) || len(seeds) < 20 {
+		t.Errorf("expected a deterministic custom source called for every review, got %d calls", len(seeds))
+	}
+	if want := CardIDSeedStrategy(Card{ID: 1, Reps: 6, Stability: 80, Difficulty: 5}, now); seeds[0] != want {
+		t.Errorf("expected the source to receive the strategy's seed, got=%q want=%q", seeds[0], want)
+	}
+
+	p.FuzzSource = func(string) PRNG {
+		return func() float64 { return math.Nextafter(1, 0) }
+	}
+	p.MaximumInterval = 50
+	if got := p.ApplyFuzz(80, 80, true); got != 50 {
+		t.Errorf("expected the top of the fuzz range capped at MaximumInterval, got=%v", got)
+	}
+	minInterval, _ := getFuzzRange(80, 80, 50)
+	for _, u := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		p.FuzzSource = func(string) PRNG {
+			return func() float64 { return u }
+		}
+		if got := p.ApplyFuzz(80, 80, true); got != float64(minInterval) {
+			t.Errorf("source %v: expected the bottom of the fuzz range, got=%v", u, got)
+		}
+	}
+}
+
+func TestIntervalModifier(t *testing.T) {
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	card := Card{
+		Due: now, Stability: 30, Difficulty: 5, State: Review,
+		LastReview: now.AddDate(0, 0, -30), ScheduledDays: 30, Reps: 5,
+	}
+	var calls []Rating
+	constant := func(interval float64, c Card, grade Rating, elapsedDays float64) float64 {
+		if elapsedDays != 30 || c.Stability == card.Stability {
+			t.Errorf("expected the elapsed days and the new memory state, got elapsed=%v card=%+v", elapsedDays, c)
+		}
+		calls = append(calls, grade)
+		return 10
+	}
+
+	for _, tc := range []struct {
+		shortTerm bool
+		want      map[Rating]uint64
+	}{
+		{true, map[Rating]uint64{Hard: 10, Good: 11, Easy: 12}},
+		{false, map[Rating]uint64{Again: 10, Hard: 11, Good: 12, Easy: 13}},
+	} {
+		calls = nil
+		p := DefaultParam()
+		p.EnableShortTerm = tc.shortTerm
+		p.IntervalModifier = constant
+		log, err := NewFSRS(p).Repeat(card, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(calls) != len(tc.want) {
+			t.Errorf("shortTerm=%v: expected %d calls, got=%v", tc.shortTerm, len(tc.want), calls)
+		}
+		for grade, days := range tc.want {
+			if got := log[grade].Card.ScheduledDays; got != days {
+				t.Errorf("shortTerm=%v %v: expected %d days after restoring the order, got=%d", tc.shortTerm, grade, days, got)
+			}
+			if want := now.AddDate(0, 0, int(days)); !log[grade].Card.Due.Equal(want) {
+				t.Errorf("shortTerm=%v %v: due=%v want=%v", tc.shortTerm, grade, log[grade].Card.Due, want)
+			}
+		}
+	}
+
+	// Vacation mode: keep due dates out of a break from day 5 to day 100.
+	p := DefaultParam()
+	vacationStart, vacationEnd := 5.0, 100.0
+	p.IntervalModifier = func(interval float64, _ Card, _ Rating, _ float64) float64 {
+		if interval >= vacationStart && interval < vacationEnd {
+			return vacationEnd
+		}
+		return interval
+	}
+	log, err := NewFSRS(p).Repeat(card, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hard, good, easy := log[Hard].Card.ScheduledDays, log[Good].Card.ScheduledDays, log[Easy].Card.ScheduledDays
+	if hard != 100 || good != 101 || easy <= good {
+		t.Errorf("expected the intervals to move after the vacation in order, got hard=%d good=%d easy=%d", hard, good, easy)
+	}
+}
+
+func TestExamDate(t *testing.T) {
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	exam := now.AddDate(0, 0, 10)
+	card := Card{
+		Due: now, Stability: 30, Difficulty: 5, State: Review,
+		LastReview: now.AddDate(0, 0, -30), ScheduledDays: 30, Reps: 5,
+	}
+
+	p := DefaultParam()
+	base, err := NewFSRS(p).Repeat(card, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.ExamDate = exam
+	p.ExamRetention = 0.995
+	log, err := NewFSRS(p).Repeat(card, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Capping runs after the grades are ordered, so all of them may land on
+	// the day before the exam.
+	for _, grade := range []Rating{Hard, Good, Easy} {
+		if got := log[grade].Card.ScheduledDays; got != 9 {
+			t.Errorf("%v: expected the interval to end the day before the exam, got=%d (without exam %d)", grade, got, base[grade].Card.ScheduledDays)
+		}
+	}
+	if !reflect.DeepEqual(log[Again], base[Again]) {
+		t.Errorf("expected relearning steps to be left alone")
+	}
+
+	p.ExamRetention = 0.5
+	if log, _ := NewFSRS(p).Repeat(card, now); !reflect.DeepEqual(log, base) {
+		t.Errorf("expected no cap when the predicted retrievability at the exam is high enough")
+	}
+
+	p = DefaultParam()
+	p.ExamRetention = 0.995
+	p.ExamDate = now.AddDate(0, 0, -1)
+	withCardExam := card
+	withCardExam.ExamDate = exam
+	if log, _ := NewFSRS(p).Repeat(withCardExam, now); log[Good].Card.ScheduledDays != 9 {
+		t.Errorf("expected Card.ExamDate to override a past ExamDate, got=%d", log[Good].Card.ScheduledDays)
+	}
+	if log, _ := NewFSRS(p).Repeat(card, now); log[Good].Card.ScheduledDays != base[Good].Card.ScheduledDays {
+		t.Errorf("expected a past exam to have no effect")
+	}
+	replay, err := NewFSRS(p).Reschedule(withCardExam, []ReviewHistory{
+		{Rating: Manual, State: StatePtr(Review), Review: card.LastReview, Due: now, Stability: card.Stability, Difficulty: card.Difficulty},
+		{Rating: Good, Review: now},
+	}, RescheduleOptions{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := replay.Collections[1].Card.ScheduledDays; got != 9 {
+		t.Errorf("expected Reschedule to honor Card.ExamDate, got=%d", got)
+	}
+
+	young := card
+	young.Stability, young.ScheduledDays, young.LastReview = 2, 2, now.AddDate(0, 0, -2)
+	p = DefaultParam()
+	p.ExamDate = now.AddDate(0, 0, 30)
+	before, _ := NewFSRS(p).Next(young, now, Good)
+	p.ExamRetention = 0.97
+	p.ExamRampDays = 40
+	ramped, _ := NewFSRS(p).Next(young, now, Good)
+	if ramped.Card.ScheduledDays >= before.Card.ScheduledDays {
+		t.Errorf("expected the ramp to raise retention and shorten the interval, got=%d before=%d", ramped.Card.ScheduledDays, before.Card.ScheduledDays)
+	}
+
+	p.ExamRetention = 1.5
+	if err := p.Validate(); !errors.Is(err, ErrInvalidRetention) {
+		t.Errorf("expected ErrInvalidRetention, got=%v", err)
+	}
+}
+
+func TestDesiredRetention(t *testing.T) {
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	card := Card{
+		Due: now, Stability: 30, Difficulty: 5, State: Review,
+		LastReview: now.AddDate(0, 0, -30), ScheduledDays: 30, Reps: 5,
+	}
+
+	p := DefaultParam()
+	p.RequestRetention = 0.95
+	want, _ := NewFSRS(p).Repeat(card, now)
+
+	f := NewFSRS(DefaultParam())
+	mustKnow := card
+	mustKnow.DesiredRetention = 0.95
+	got, err := f.Repeat(mustKnow, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, grade := range []Rating{Again, Hard, Good, Easy} {
+		if got[grade].Card.Due != want[grade].Card.Due {
+			t.Errorf("%v: expected the card's retention to replace RequestRetention, got=%v want=%v", grade, got[grade].Card.Due, want[grade].Card.Due)
+		}
+		if got[grade].ReviewLog.DesiredRetention != 0.95 || got[grade].Card.DesiredRetention != 0.95 {
+			t.Errorf("%v: expected the retention to be kept and logged, got=%+v", grade, got[grade])
+		}
+	}
+	if info, _ := f.Next(card, now, Good); info.ReviewLog.DesiredRetention != 0.9 || info.Card.Due == got[Good].Card.Due {
+		t.Errorf("expected cards without an override to use RequestRetention, got=%+v", info)
+	}
+
+	mustKnow.DesiredRetention = 1.2
+	if _, err := f.Next(mustKnow, now, Good); !errors.Is(err, ErrInvalidRetention) {
+		t.Errorf("expected ErrInvalidRetention, got=%v", err)
+	}
+
+	reviews := []ReviewHistory{
+		{Rating: Good, Review: now},
+		{Rating: Good, Review: now.AddDate(0, 0, 3)},
+	}
+	result, err := f.Reschedule(NewCard(now), reviews, RescheduleOptions{DesiredRetention: 0.8, Now: now.AddDate(0, 0, 4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range result.Collections {
+		if item.Card.DesiredRetention != 0.8 || item.ReviewLog.DesiredRetention != 0.8 {
+			t.Errorf("review %d: expected Reschedule to replay and record the override, got=%+v", i, item)
+		}
+	}
+	if result.RescheduleItem == nil || result.RescheduleItem.Card.DesiredRetention != 0.8 {
+		t.Errorf("expected the reschedule item to carry the override, got=%+v", result.RescheduleItem)
+	}
+}
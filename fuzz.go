@@ -1,6 +1,10 @@
 package fsrs
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // fuzzRange defines a bucket for interval fuzz randomization.
 type fuzzRange struct {
@@ -29,13 +33,56 @@ func (p *Parameters) ApplyFuzz(interval float64, elapsedDays float64, enableFuzz
 	if !enableFuzz || interval < 2.5 {
 		return interval
 	}
-	return applyFuzz(interval, elapsedDays, p.MaximumInterval, p.seed)
+	return p.fuzzInterval(interval, elapsedDays)
 }
 
-func applyFuzz(interval float64, elapsedDays float64, maximumInterval float64, seed string) float64 {
-	generator := Alea(seed)
-	fuzzFactor := generator.Double()
+// fuzzInterval picks the fuzzed interval, weighting the days of the fuzz
+// range when a LoadBalancer, EasyDays or sibling due dates are configured and
+// the review time is known.
+func (p *Parameters) fuzzInterval(interval float64, elapsedDays float64) float64 {
+	if (p.LoadBalancer != nil || len(p.EasyDays) == 7 || len(p.siblingDues) > 0) && !p.reviewTime.IsZero() {
+		return p.balanceInterval(interval, elapsedDays)
+	}
+	return applyFuzz(interval, elapsedDays, p.MaximumInterval, p.fuzzFactor())
+}
+
+// SeedStrategy returns the fuzz seed of a review at now. card is the card
+// being reviewed with LastReview already set to now and Reps incremented.
+type SeedStrategy func(card Card, now time.Time) string
+
+// DefaultSeedStrategy combines the review time in milliseconds, the review
+// count and the product of difficulty and stability, as ts-fsrs does.
+func DefaultSeedStrategy(card Card, now time.Time) string {
+	return fmt.Sprintf("%d_%d_%f", now.UnixMilli(), card.Reps, card.Difficulty*card.Stability)
+}
+
+// CardIDSeedStrategy extends DefaultSeedStrategy with Card.ID, so that cards
+// with identical state reviewed at the same time get independent fuzz, like
+// ts-fsrs' GenSeedStrategyWithCardId.
+func CardIDSeedStrategy(card Card, now time.Time) string {
+	return fmt.Sprintf("%s_%d", DefaultSeedStrategy(card, now), card.ID)
+}
+
+// FuzzSource returns the random number generator used to fuzz a review
+// with the given seed. The PRNG must return values in [0, 1).
+type FuzzSource func(seed string) PRNG
+
+// fuzzFactor draws the random number in [0, 1) that fuzzes the current
+// review. Alea's output is refined with [PRNG.Double] as in ts-fsrs, which is
+// only safe for its 2^-32 grid, so a FuzzSource is used as is, clamped to
+// [0, 1), with NaN and infinite values treated as 0.
+func (p *Parameters) fuzzFactor() float64 {
+	if p.FuzzSource != nil {
+		u := p.FuzzSource(p.seed)()
+		if !isFinite(u) {
+			return 0
+		}
+		return clamp(u, 0, math.Nextafter(1, 0))
+	}
+	return Alea(p.seed).Double()
+}
 
+func applyFuzz(interval float64, elapsedDays float64, maximumInterval float64, fuzzFactor float64) float64 {
 	minInterval, maxInterval := getFuzzRange(interval, elapsedDays, maximumInterval)
 
 	return math.Floor(fuzzFactor*float64(maxInterval-minInterval+1)) + float64(minInterval)
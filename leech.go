@@ -0,0 +1,28 @@
+package fsrs
+
+// isLeech reports whether reaching the given lapse count flags a card as a
+// leech.
+func (p *Parameters) isLeech(lapses uint64) bool {
+	if p.LeechThreshold == 0 || lapses < p.LeechThreshold {
+		return false
+	}
+	if lapses == p.LeechThreshold {
+		return true
+	}
+	return p.LeechRepeat > 0 && (lapses-p.LeechThreshold)%p.LeechRepeat == 0
+}
+
+// markLeech flags info as a leech review when it added a lapse that reaches
+// the leech threshold or one of its repeats, suspending the card if
+// LeechSuspend is set.
+func (p *Parameters) markLeech(before Card, info SchedulingInfo) SchedulingInfo {
+	if info.Card.Lapses <= before.Lapses || !p.isLeech(info.Card.Lapses) {
+		return info
+	}
+	info.ReviewLog.Leech = true
+	info.Card.Leech = true
+	if p.LeechSuspend {
+		info.Card.Suspended = true
+	}
+	return info
+}
@@ -0,0 +1,73 @@
+package fsrs
+
+import (
+	"math"
+	"time"
+)
+
+// LoadBalancer reports how many cards are already due on a given day.
+// DueCount receives the due time a candidate interval would produce and
+// should count the cards due on the same review day, as delimited by
+// Parameters.Location and Parameters.DayStartHour.
+type LoadBalancer interface {
+	DueCount(due time.Time) int
+}
+
+// LoadBalancerFunc adapts an ordinary function to the LoadBalancer interface.
+type LoadBalancerFunc func(due time.Time) int
+
+// DueCount calls f(due).
+func (f LoadBalancerFunc) DueCount(due time.Time) int {
+	return f(due)
+}
+
+// balanceInterval chooses a day from the fuzz range at random, weighting
+// each candidate like Anki's load balancer. With a LoadBalancer, days are
+// weighted by the inverse square of the cards already due and by the inverse
+// of the interval, so that empty days and shorter intervals are preferred.
+// EasyDays then scales each weight by the modifier of its weekday, and days
+// on which a sibling is due are avoided. A rule that would rule out every day
+// is ignored, easy days first. The choice is seeded the same way as plain
+// fuzz.
+func (p *Parameters) balanceInterval(interval float64, elapsedDays float64) float64 {
+	minInterval, maxInterval := getFuzzRange(interval, elapsedDays, p.MaximumInterval)
+
+	siblingDays := make(map[time.Time]bool, len(p.siblingDues))
+	for _, due := range p.siblingDues {
+		siblingDays[p.reviewDay(due)] = true
+	}
+
+	n := maxInterval - minInterval + 1
+	weights := make([]float64, 0, n)
+	eased := make([]float64, 0, n)
+	for ivl := minInterval; ivl <= maxInterval; ivl++ {
+		due := p.addDays(p.reviewTime, float64(ivl))
+		w := 1.0
+		if p.LoadBalancer != nil {
+			count := max(p.LoadBalancer.DueCount(due), 1)
+			w = 1 / math.Pow(float64(count), 2) / float64(ivl)
+		}
+		weights = append(weights, w)
+		if len(p.EasyDays) == 7 {
+			w *= p.EasyDays[p.reviewDay(due).Weekday()]
+		}
+		eased = append(eased, w)
+	}
+	if sumWeights(eased) > 0 {
+		weights = eased
+	}
+	if len(siblingDays) > 0 {
+		spaced := make([]float64, n)
+		for i := range spaced {
+			due := p.addDays(p.reviewTime, float64(minInterval+i))
+			if !siblingDays[p.reviewDay(due)] {
+				spaced[i] = weights[i]
+			}
+		}
+		if sumWeights(spaced) > 0 {
+			weights = spaced
+		}
+	}
+
+	return float64(minInterval + sampleIndex(weights, p.fuzzFactor()))
+}
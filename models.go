@@ -5,6 +5,11 @@ import (
 )
 
 type Card struct {
+	// ID identifies the card and NoteID the note it was generated from.
+	// Cards sharing a non-zero NoteID are siblings. Both are optional; the
+	// scheduler only uses them to recognise siblings.
+	ID             int64     `json:"ID"`
+	NoteID         int64     `json:"NoteID"`
 	Due            time.Time `json:"Due"`
 	Stability      float64   `json:"Stability"`
 	Difficulty     float64   `json:"Difficulty"`
@@ -14,11 +19,23 @@ type Card struct {
 	State          State     `json:"State"`
 	LastReview     time.Time `json:"LastReview"`
 	RemainingSteps int       `json:"RemainingSteps"`
+	// Leech is set once the card reaches Parameters.LeechThreshold lapses.
+	Leech bool `json:"Leech"`
+	// Suspended cards are left out of study queues and rejected by
+	// FSRS.Next and FSRS.Repeat until the flag is cleared.
+	Suspended bool `json:"Suspended"`
+	// ExamDate, when non-zero, overrides Parameters.ExamDate for this card.
+	ExamDate time.Time `json:"ExamDate"`
+	// DesiredRetention, when non-zero, overrides Parameters.RequestRetention
+	// for this card, e.g. to give a deck its own target. Must be 0 or in
+	// (0, 1].
+	DesiredRetention float64 `json:"DesiredRetention"`
 }
 
 // NewCard returns a new Card with default values. If now is provided, Due is
 // set to now; otherwise Due defaults to time.Now(), aligning with ts-fsrs
-// createEmptyCard() which uses new Date() as the default.
+// createEmptyCard() which uses new Date() as the default. Use [FSRS.NewCard]
+// to take the time from an injected Clock instead.
 func NewCard(now ...time.Time) Card {
 	card := Card{}
 	if len(now) > 0 {
@@ -38,6 +55,11 @@ type ReviewLog struct {
 	Stability      float64   `json:"Stability"`
 	Difficulty     float64   `json:"Difficulty"`
 	RemainingSteps int       `json:"RemainingSteps"`
+	// Leech marks the review whose lapse turned the card into a leech.
+	Leech bool `json:"Leech"`
+	// DesiredRetention is the retention the review's intervals were
+	// computed for. It is 0 for logs not produced by the scheduler.
+	DesiredRetention float64 `json:"DesiredRetention"`
 }
 
 type SchedulingInfo struct {
@@ -142,13 +164,17 @@ type RescheduleResult struct {
 	RescheduleItem *SchedulingInfo  `json:"RescheduleItem"`
 }
 
-// RescheduleOptions configures the behaviour of [FSRS.Reschedule].
+// RescheduleOptions configures the behaviour of [FSRS.Reschedule]. Now is
+// the review time of the final reschedule entry; the zero value means
+// [FSRS.Now]. DesiredRetention, when non-zero, replaces the card's own
+// DesiredRetention for the replay and is kept on the resulting cards.
 type RescheduleOptions struct {
 	SkipManual        bool      `json:"SkipManual"`
 	UpdateMemoryState bool      `json:"UpdateMemoryState"`
 	Now               time.Time `json:"Now"`
 	FirstDue          time.Time `json:"FirstDue"`
 	SortReviews       bool      `json:"SortReviews"`
+	DesiredRetention  float64   `json:"DesiredRetention"`
 }
 
 // StatePtr returns a pointer to the given State value. It is a convenience
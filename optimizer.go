@@ -0,0 +1,229 @@
+package fsrs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// OptimizerOptions configures [Optimize]. Start from
+// [DefaultOptimizerOptions] and override individual fields; zero numeric
+// fields fall back to their defaults.
+type OptimizerOptions struct {
+	Epochs          int       `json:"Epochs"`
+	BatchSize       int       `json:"BatchSize"`
+	LearningRate    float64   `json:"LearningRate"`
+	EnableShortTerm bool      `json:"EnableShortTerm"`
+	RelearningSteps []float64 `json:"RelearningSteps"`
+	// InitialWeights is the starting point of the search. When nil,
+	// DefaultWeights is used with W[0..3] replaced by the result of
+	// PretrainInitialStability whenever there is enough data for it.
+	InitialWeights *Weights `json:"InitialWeights"`
+	// Seed controls the order in which histories are visited each epoch.
+	Seed int `json:"Seed"`
+}
+
+// DefaultOptimizerOptions returns the options used by fsrs-rs: 5 epochs,
+// batches of 512 reviews, learning rate 0.04 and short-term scheduling enabled.
+func DefaultOptimizerOptions() OptimizerOptions {
+	return OptimizerOptions{
+		Epochs:          5,
+		BatchSize:       512,
+		LearningRate:    4e-2,
+		EnableShortTerm: true,
+		RelearningSteps: DefaultRelearningSteps(),
+	}
+}
+
+const (
+	adamBeta1      = 0.9
+	adamBeta2      = 0.999
+	adamEpsilon    = 1e-8
+	gradientStep   = 1e-4
+	minProbability = 1e-4
+)
+
+// Optimize fits all 21 weights to the given review histories by minimizing
+// the log loss of the recall predictions made by the scheduler's own memory
+// model. Each history is the chronologically ordered review sequence of one
+// card; every review after the first with DeltaT > 0 is a training target,
+// labelled as recalled unless it was rated Again.
+//
+// Gradients are estimated with central differences and applied with Adam
+// under a cosine-annealed learning rate. After every step the weights are
+// clipped to the ranges enforced by [NewFSRS]. Returns ErrNotEnoughData when
+// no history contains a training target, and ctx.Err() if ctx is cancelled.
+func Optimize(ctx context.Context, histories []ReviewEntries, opts OptimizerOptions) (Weights, error) {
+	opts = opts.withDefaults()
+	if err := validateHistories(histories); err != nil {
+		return Weights{}, err
+	}
+
+	train := make([]ReviewEntries, 0, len(histories))
+	total := 0
+	for _, h := range histories {
+		if n := countTargets(h); n > 0 {
+			train = append(train, h)
+			total += n
+		}
+	}
+	if total == 0 {
+		return Weights{}, ErrNotEnoughData
+	}
+
+	p := Parameters{
+		RequestRetention: 0.9,
+		MaximumInterval:  36500,
+		W:                DefaultWeights(),
+		EnableShortTerm:  opts.EnableShortTerm,
+		RelearningSteps:  opts.RelearningSteps,
+	}
+	if opts.InitialWeights != nil {
+		p.W = *opts.InitialWeights
+	} else if s0, err := PretrainInitialStability(FirstReviews(train)); err == nil {
+		copy(p.W[:4], s0[:])
+	}
+	clipParameters(&p)
+
+	batches := makeBatches(train, opts.BatchSize)
+	steps := len(batches) * opts.Epochs
+	order := make([]int, len(batches))
+	for i := range order {
+		order[i] = i
+	}
+	shuffle := Alea(opts.Seed)
+
+	var m, v Weights
+	step := 0
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		for i := len(order) - 1; i > 0; i-- {
+			j := int(shuffle() * float64(i+1))
+			order[i], order[j] = order[j], order[i]
+		}
+		for _, bi := range order {
+			if err := ctx.Err(); err != nil {
+				return Weights{}, err
+			}
+			grad := p.lossGradient(batches[bi])
+			step++
+			lr := opts.LearningRate * 0.5 * (1 + math.Cos(math.Pi*float64(step-1)/float64(steps)))
+			for k := range p.W {
+				m[k] = adamBeta1*m[k] + (1-adamBeta1)*grad[k]
+				v[k] = adamBeta2*v[k] + (1-adamBeta2)*grad[k]*grad[k]
+				mHat := m[k] / (1 - math.Pow(adamBeta1, float64(step)))
+				vHat := v[k] / (1 - math.Pow(adamBeta2, float64(step)))
+				p.W[k] -= lr * mHat / (math.Sqrt(vHat) + adamEpsilon)
+			}
+			clipParameters(&p)
+		}
+	}
+
+	if err := validateFiniteWeights(p.W[:]); err != nil {
+		return Weights{}, err
+	}
+	return p.W, nil
+}
+
+func (o OptimizerOptions) withDefaults() OptimizerOptions {
+	d := DefaultOptimizerOptions()
+	if o.Epochs <= 0 {
+		o.Epochs = d.Epochs
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = d.BatchSize
+	}
+	if !isFinite(o.LearningRate) || o.LearningRate <= 0 {
+		o.LearningRate = d.LearningRate
+	}
+	return o
+}
+
+func validateHistories(histories []ReviewEntries) error {
+	for i, h := range histories {
+		for j, review := range h {
+			if review.Rating < Again || review.Rating > Easy {
+				return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: invalid rating %d in history %d at review %d, must be 1-4", review.Rating, i, j)}
+			}
+			if !isFinite(review.DeltaT) || review.DeltaT < 0 {
+				return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: invalid delta_t in history %d at review %d, must be a finite non-negative number", i, j)}
+			}
+		}
+	}
+	return nil
+}
+
+func countTargets(history ReviewEntries) int {
+	n := 0
+	for i := 1; i < len(history); i++ {
+		if history[i].DeltaT > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// makeBatches groups whole histories into batches holding roughly size
+// training targets each, so every history is replayed exactly once per batch.
+func makeBatches(histories []ReviewEntries, size int) [][]ReviewEntries {
+	var batches [][]ReviewEntries
+	var cur []ReviewEntries
+	n := 0
+	for _, h := range histories {
+		cur = append(cur, h)
+		n += countTargets(h)
+		if n >= size {
+			batches = append(batches, cur)
+			cur, n = nil, 0
+		}
+	}
+	if len(cur) > 0 {
+		batches = append(batches, cur)
+	}
+	return batches
+}
+
+// logLoss returns the summed binary cross-entropy of the recall predictions
+// over every training target in histories, and the number of targets.
+func (p *Parameters) logLoss(histories []ReviewEntries) (float64, int) {
+	decay, factor := p.decayAndFactor()
+	loss := 0.0
+	n := 0
+	for _, h := range histories {
+		var state MemoryState
+		for i, review := range h {
+			if i > 0 && review.DeltaT > 0 {
+				r := clamp(forgettingCurve(review.DeltaT, state.Stability, decay, factor), minProbability, 1-minProbability)
+				if review.Rating == Again {
+					loss -= math.Log(1 - r)
+				} else {
+					loss -= math.Log(r)
+				}
+				n++
+			}
+			state = p.nextStateInner(&state, p.RequestRetention, review.DeltaT, review.Rating, decay, factor).Memory
+		}
+	}
+	return loss, n
+}
+
+// lossGradient estimates the gradient of the mean log loss over batch with
+// central differences, evaluating each weight in its own goroutine.
+func (p *Parameters) lossGradient(batch []ReviewEntries) Weights {
+	var grad Weights
+	var wg sync.WaitGroup
+	for k := range p.W {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			plus, minus := *p, *p
+			plus.W[k] += gradientStep
+			minus.W[k] -= gradientStep
+			lossPlus, n := plus.logLoss(batch)
+			lossMinus, _ := minus.logLoss(batch)
+			grad[k] = (lossPlus - lossMinus) / (2 * gradientStep * float64(n))
+		}(k)
+	}
+	wg.Wait()
+	return grad
+}
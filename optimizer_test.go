@@ -0,0 +1,146 @@
+package fsrs
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// syntheticHistories simulates n cards reviewed at their scheduled intervals
+// under weights w, sampling recall from the forgetting curve.
+func syntheticHistories(w Weights, n, reviews int, seed string) []ReviewEntries {
+	p := DefaultParam()
+	p.W = w
+	decay, factor := p.decayAndFactor()
+	rng := Alea(seed)
+	histories := make([]ReviewEntries, 0, n)
+	for i := 0; i < n; i++ {
+		first := Rating(1 + int(rng()*4))
+		h := ReviewEntries{{Rating: first, DeltaT: 0}}
+		state := p.nextStateInner(nil, 0.9, 0, first, decay, factor)
+		for j := 1; j < reviews; j++ {
+			deltaT := math.Max(1, math.Round(state.Interval*(0.5+rng())))
+			rating := Good
+			if rng() > forgettingCurve(deltaT, state.Memory.Stability, decay, factor) {
+				rating = Again
+			} else if x := rng(); x < 0.15 {
+				rating = Hard
+			} else if x > 0.9 {
+				rating = Easy
+			}
+			h = append(h, ReviewEntry{Rating: rating, DeltaT: deltaT})
+			state = p.nextStateInner(&state.Memory, 0.9, deltaT, rating, decay, factor)
+		}
+		histories = append(histories, h)
+	}
+	return histories
+}
+
+func TestOptimizeReducesLoss(t *testing.T) {
+	target := DefaultWeights()
+	target[0], target[1], target[2], target[3] = 1.0, 3.0, 8.0, 20.0
+	target[8] = 1.2
+	histories := syntheticHistories(target, 300, 8, "optimize")
+
+	opts := DefaultOptimizerOptions()
+	opts.Epochs = 3
+	opts.BatchSize = 256
+	w, err := Optimize(context.Background(), histories, opts)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+
+	before := DefaultParam()
+	after := DefaultParam()
+	after.W = w
+	lossBefore, n := before.logLoss(histories)
+	lossAfter, _ := after.logLoss(histories)
+	if lossAfter >= lossBefore {
+		t.Errorf("expected loss to decrease: before=%.4f after=%.4f (n=%d)", lossBefore/float64(n), lossAfter/float64(n), n)
+	}
+
+	clipped := after
+	clipParameters(&clipped)
+	if clipped.W != w {
+		t.Errorf("optimized weights outside clip ranges: %v", w)
+	}
+}
+
+func TestOptimizeErrors(t *testing.T) {
+	t.Run("no training targets", func(t *testing.T) {
+		histories := []ReviewEntries{{{Rating: Good, DeltaT: 0}}, {{Rating: Again, DeltaT: 0}, {Rating: Good, DeltaT: 0}}}
+		_, err := Optimize(context.Background(), histories, DefaultOptimizerOptions())
+		if !errors.Is(err, ErrNotEnoughData) {
+			t.Errorf("expected ErrNotEnoughData, got=%v", err)
+		}
+	})
+
+	t.Run("invalid rating", func(t *testing.T) {
+		histories := []ReviewEntries{{{Rating: Good, DeltaT: 0}, {Rating: 5, DeltaT: 1}}}
+		_, err := Optimize(context.Background(), histories, DefaultOptimizerOptions())
+		var fsrsErr *Error
+		if !errors.As(err, &fsrsErr) || fsrsErr.Code != ErrCodeInvalidInput {
+			t.Errorf("expected ErrCodeInvalidInput, got=%v", err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		histories := syntheticHistories(DefaultWeights(), 10, 4, "cancel")
+		_, err := Optimize(ctx, histories, DefaultOptimizerOptions())
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got=%v", err)
+		}
+	})
+}
+
+func TestPretrainInitialStability(t *testing.T) {
+	target := DefaultWeights()
+	target[0], target[1], target[2], target[3] = 0.5, 2.0, 6.0, 25.0
+	histories := syntheticHistories(target, 4000, 2, "pretrain")
+
+	s0, err := PretrainInitialStability(FirstReviews(histories))
+	if err != nil {
+		t.Fatalf("PretrainInitialStability returned error: %v", err)
+	}
+	for i := 1; i < 4; i++ {
+		if s0[i] < s0[i-1] {
+			t.Errorf("expected non-decreasing initial stabilities, got=%v", s0)
+		}
+	}
+	for i := range s0 {
+		if math.Abs(math.Log(s0[i]/target[i])) > math.Log(2) {
+			t.Errorf("W[%d]: expected close to %v, got=%v", i, target[i], s0[i])
+		}
+	}
+}
+
+func TestPretrainInitialStabilityFillsMissingRatings(t *testing.T) {
+	pairs := make([]FirstReview, 0, 40)
+	for i := 0; i < 20; i++ {
+		pairs = append(pairs,
+			FirstReview{Rating: Good, DeltaT: 3, Recalled: i%10 != 0},
+			FirstReview{Rating: Hard, DeltaT: 1, Recalled: i%5 != 0},
+		)
+	}
+	pairs = append(pairs, FirstReview{Rating: Easy, DeltaT: 10, Recalled: true})
+
+	s0, err := PretrainInitialStability(pairs)
+	if err != nil {
+		t.Fatalf("PretrainInitialStability returned error: %v", err)
+	}
+	d := DefaultWeights()
+	if got, want := s0[0]/s0[1], d[0]/d[1]; math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected Again to keep the default Again/Hard ratio %v, got=%v", want, got)
+	}
+	if got, want := s0[3]/s0[2], d[3]/d[2]; math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected Easy to keep the default Easy/Good ratio %v, got=%v", want, got)
+	}
+
+	_, err = PretrainInitialStability(pairs[len(pairs)-1:])
+	if !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("expected ErrNotEnoughData, got=%v", err)
+	}
+}
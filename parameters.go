@@ -1,8 +1,10 @@
 package fsrs
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"time"
 )
 
 // DefaultLearningSteps returns the default learning step delays in minutes: {1, 10}.
@@ -25,14 +27,86 @@ type Parameters struct {
 	EnableFuzz       bool      `json:"EnableFuzz"`
 	LearningSteps    []float64 `json:"LearningSteps"`
 	RelearningSteps  []float64 `json:"RelearningSteps"`
+	// LoadBalancer, when set and fuzz is enabled, makes the scheduler prefer
+	// days with fewer due cards inside the fuzz range. See [LoadBalancer].
+	LoadBalancer LoadBalancer `json:"-"`
+	// EasyDays optionally scales how much the scheduler wants to place a due
+	// date on each weekday, indexed by time.Weekday: 1 is a normal day, 0.5
+	// halves its chance within the fuzz range and 0 avoids it whenever another
+	// day in the range is allowed. Must be empty or hold 7 values in [0, 1].
+	// It only takes effect when fuzz is enabled.
+	EasyDays []float64 `json:"EasyDays"`
+	// Location is the time zone in which review days are counted; nil means
+	// UTC. Together with DayStartHour it decides when a new day begins for
+	// elapsed days, Forget, Reschedule and the weekday and load of due dates.
+	// In JSON it is stored as its IANA name under "Location", so only
+	// zones that time.LoadLocation can find survive a round trip.
+	Location *time.Location `json:"-"`
+	// DayStartHour is the hour in Location at which a new review day
+	// begins, e.g. 4 so that reviews until 4am count towards the previous
+	// day. Must be in [0, 23].
+	DayStartHour int `json:"DayStartHour"`
+	// LeechThreshold is the number of lapses at which a card becomes a
+	// leech; 0 disables leech detection. After that, the card is flagged
+	// again every LeechRepeat lapses, or never when LeechRepeat is 0.
+	LeechThreshold uint64 `json:"LeechThreshold"`
+	LeechRepeat    uint64 `json:"LeechRepeat"`
+	// LeechSuspend suspends a card whenever it is flagged as a leech.
+	LeechSuspend bool `json:"LeechSuspend"`
+	// Strategy, when set, replaces the built-in scheduling selected by
+	// EnableShortTerm for Next, Repeat and everything built on them.
+	Strategy StrategyFunc `json:"-"`
+	// SeedStrategy builds the fuzz seed of each review; nil means
+	// DefaultSeedStrategy. See also CardIDSeedStrategy.
+	SeedStrategy SeedStrategy `json:"-"`
+	// FuzzSource turns the seed into the random numbers used for fuzz; nil
+	// means Alea.
+	FuzzSource FuzzSource `json:"-"`
+	// IntervalModifier, when set, may adjust every interval in days the
+	// scheduler computes, after clamping and fuzz. See [IntervalModifier].
+	IntervalModifier IntervalModifier `json:"-"`
+	// ExamDate, when non-zero, is the date of an exam before which every
+	// card should be reviewed: an interval that would skip past it is cut
+	// to end the day before whenever the forgetting curve predicts a
+	// retrievability below ExamRetention at the exam. Card.ExamDate
+	// overrides it per card. It has no effect from the exam day on. The cut
+	// is applied after the grades' intervals are put in order, so it may
+	// give Hard, Good and Easy the same interval; see [IntervalModifier].
+	ExamDate time.Time `json:"ExamDate"`
+	// ExamRetention is the retrievability to reach at the exam; 0 means
+	// RequestRetention. Must be 0 or in (0, 1].
+	ExamRetention float64 `json:"ExamRetention"`
+	// ExamRampDays, when positive, raises the retention used for intervals
+	// linearly from RequestRetention to ExamRetention over the last
+	// ExamRampDays days before the exam.
+	ExamRampDays float64 `json:"ExamRampDays"`
 	// seed is populated internally by the Scheduler before fuzz is applied.
 	// When calling [Parameters.ApplyFuzz] directly without going through a
 	// Scheduler (e.g. [FSRS.Repeat] or [FSRS.Next]), seed will be empty,
 	// producing deterministic but predictable fuzz output. To avoid this,
 	// always use the Scheduler-based APIs.
 	seed string
+	// reviewTime is populated by the Scheduler alongside seed so that fuzz
+	// can look up the calendar day of each candidate interval.
+	reviewTime time.Time
+	// examDate is the exam date that applies to the card being scheduled,
+	// set by the Scheduler from Card.ExamDate or ExamDate.
+	examDate time.Time
+	// siblingDues holds the due dates of the reviewed card's siblings while
+	// scheduling through [FSRS.NextWithSiblings].
+	siblingDues []time.Time
 }
 
+// IntervalModifier returns the interval in days to use for card, the result
+// of a review with grade after elapsedDays, given the computed interval. card
+// already holds its new memory state. The result is rounded and clamped to
+// [1, MaximumInterval], and the scheduler then restores the ordering of the
+// grades' intervals (Hard <= Good < Easy, and Again < Hard in the long-term
+// scheduler), so a modifier only needs to look at one interval at a time.
+// The one exception is an exam date: intervals that would skip past it are
+// cut afterwards, so every grade may end up due the day before the exam.
+type IntervalModifier func(interval float64, card Card, grade Rating, elapsedDays float64) float64
+
 // DefaultParam returns a Parameters value initialized with sensible defaults:
 // retention 0.9, max interval 36500, default weights, and short-term enabled.
 func DefaultParam() Parameters {
@@ -52,9 +126,20 @@ func DefaultParam() Parameters {
 
 // Validate checks that all parameters are within valid ranges. It verifies:
 // weights are finite and W[20] > 0, RequestRetention is in (0, 1],
-// MaximumInterval is in (0, 36500], and LearningSteps/RelearningSteps
-// contain only finite non-negative values.
+// MaximumInterval is in (0, 36500], LearningSteps/RelearningSteps
+// contain only finite non-negative values, EasyDays is empty or holds
+// 7 values in [0, 1], DayStartHour is in [0, 23], ExamRetention is 0 or
+// in (0, 1] and ExamRampDays is finite and >= 0.
 func (p *Parameters) Validate() error {
+	if err := p.validateModel(); err != nil {
+		return err
+	}
+	return p.validateOptions()
+}
+
+// validateModel checks the memory model and interval parameters: the
+// weights, RequestRetention, MaximumInterval and the learning steps.
+func (p *Parameters) validateModel() error {
 	for i, w := range p.W {
 		if math.IsNaN(w) || math.IsInf(w, 0) {
 			return &Error{Code: ErrCodeInvalidWeightsValue, Message: fmt.Sprintf("fsrs: invalid weight W[%d]: must be finite", i)}
@@ -90,6 +175,106 @@ func (p *Parameters) Validate() error {
 	return nil
 }
 
+// validateOptions checks the scheduling options layered on top of the
+// model: EasyDays, the exam settings and DayStartHour.
+func (p *Parameters) validateOptions() error {
+	if len(p.EasyDays) != 0 && len(p.EasyDays) != 7 {
+		return &Error{Code: ErrCodeInvalidEasyDays, Message: fmt.Sprintf("fsrs: invalid EasyDays: must be empty or have 7 values, got %d", len(p.EasyDays))}
+	}
+	for i, d := range p.EasyDays {
+		if math.IsNaN(d) || d < 0 || d > 1 {
+			return &Error{Code: ErrCodeInvalidEasyDays, Message: fmt.Sprintf("fsrs: invalid EasyDays[%d]: must be in [0, 1], got %v", i, d)}
+		}
+	}
+
+	if math.IsNaN(p.ExamRetention) || p.ExamRetention < 0 || p.ExamRetention > 1 {
+		return &Error{Code: ErrCodeInvalidRetention, Message: fmt.Sprintf("fsrs: invalid ExamRetention: must be 0 or in (0, 1], got %v", p.ExamRetention)}
+	}
+	if !isFinite(p.ExamRampDays) || p.ExamRampDays < 0 {
+		return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: invalid ExamRampDays: must be finite and >= 0, got %v", p.ExamRampDays)}
+	}
+
+	if p.DayStartHour < 0 || p.DayStartHour > 23 {
+		return &Error{Code: ErrCodeInvalidDayStartHour, Message: fmt.Sprintf("fsrs: invalid DayStartHour: must be in [0, 23], got %d", p.DayStartHour)}
+	}
+
+	return nil
+}
+
+// daysBetween counts the review days between last and cur using the
+// configured Location and DayStartHour.
+func (p *Parameters) daysBetween(last, cur time.Time) uint64 {
+	return dayDiff(last, cur, p.location(), p.DayStartHour)
+}
+
+// reviewDay returns the review day containing t. See daysBetween.
+func (p *Parameters) reviewDay(t time.Time) time.Time {
+	return reviewDay(t, p.location(), p.DayStartHour)
+}
+
+// addDays returns t moved forward by days review days, at most
+// MaximumInterval. Whole days are added on the calendar of Location, so the
+// due date keeps its local time of day across daylight saving changes.
+func (p *Parameters) addDays(t time.Time, days float64) time.Time {
+	days = math.Min(days, p.MaximumInterval)
+	whole := math.Floor(days)
+	local := t.In(p.location())
+	return t.Add(local.AddDate(0, 0, int(whole)).Sub(local) + daysToDuration(days-whole, 1))
+}
+
+// MarshalJSON encodes p with Location as its IANA name. It fails for a
+// Location, such as a time.FixedZone, that time.LoadLocation cannot load
+// back.
+func (p Parameters) MarshalJSON() ([]byte, error) {
+	type parameters Parameters
+	var name string
+	if p.Location != nil {
+		name = p.Location.String()
+		if _, err := time.LoadLocation(name); err != nil {
+			return nil, &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: Location %q has no IANA name", name)}
+		}
+	}
+	return json.Marshal(struct {
+		parameters
+		Location string `json:"Location,omitempty"`
+	}{parameters(p), name})
+}
+
+// UnmarshalJSON decodes p, loading Location from its IANA name. Fields
+// missing from data are left unchanged.
+func (p *Parameters) UnmarshalJSON(data []byte) error {
+	type parameters Parameters
+	if err := json.Unmarshal(data, (*parameters)(p)); err != nil {
+		return err
+	}
+	var zone struct {
+		Location *string `json:"Location"`
+	}
+	if err := json.Unmarshal(data, &zone); err != nil {
+		return err
+	}
+	if zone.Location == nil {
+		return nil
+	}
+	if *zone.Location == "" {
+		p.Location = nil
+		return nil
+	}
+	loc, err := time.LoadLocation(*zone.Location)
+	if err != nil {
+		return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: unknown Location %q", *zone.Location)}
+	}
+	p.Location = loc
+	return nil
+}
+
+func (p *Parameters) location() *time.Location {
+	if p.Location == nil {
+		return time.UTC
+	}
+	return p.Location
+}
+
 func clamp(v, lo, hi float64) float64 {
 	return math.Max(lo, math.Min(v, hi))
 }
@@ -143,6 +328,10 @@ func clipParameters(p *Parameters) {
 
 func (p *Parameters) ForgettingCurve(elapsedDays float64, stability float64) float64 {
 	decay, factor := p.decayAndFactor()
+	return forgettingCurve(elapsedDays, stability, decay, factor)
+}
+
+func forgettingCurve(elapsedDays, stability, decay, factor float64) float64 {
 	stability = constrainStability(stability)
 	return math.Pow(1+factor*elapsedDays/stability, decay)
 }
@@ -174,7 +363,7 @@ func (p *Parameters) nextStateInner(current *MemoryState, desiredRetention, elap
 		if elapsed == 0 && p.EnableShortTerm {
 			newS = p.shortTermStability(current.Stability, grade)
 		} else {
-			retrievability := p.ForgettingCurve(elapsed, current.Stability)
+			retrievability := forgettingCurve(elapsed, current.Stability, decay, factor)
 			if grade == Again {
 				newS = p.nextForgetStability(current.Difficulty, current.Stability, retrievability)
 			} else {
@@ -0,0 +1,176 @@
+package fsrs
+
+import (
+	"math"
+	"sort"
+)
+
+// FirstReview pairs the first rating a card received with the outcome of the
+// review that followed it. It is the training sample for
+// [PretrainInitialStability].
+type FirstReview struct {
+	Rating   Rating  `json:"Rating"`
+	DeltaT   float64 `json:"DeltaT"`
+	Recalled bool    `json:"Recalled"`
+}
+
+const (
+	// pretrainMinSamples is the number of first reviews a rating needs before
+	// its initial stability is fitted instead of inferred from the others.
+	pretrainMinSamples = 10
+	pretrainSMin       = 0.1
+	pretrainSMax       = 100.0
+)
+
+// FirstReviews extracts one FirstReview from every history whose second
+// review happened on a later day than the first. Histories with same-day
+// learning steps between the first two reviews are skipped, as in fsrs-rs.
+func FirstReviews(histories []ReviewEntries) []FirstReview {
+	out := make([]FirstReview, 0, len(histories))
+	for _, h := range histories {
+		if len(h) < 2 || h[1].DeltaT <= 0 || h[0].Rating < Again || h[0].Rating > Easy {
+			continue
+		}
+		out = append(out, FirstReview{
+			Rating:   h[0].Rating,
+			DeltaT:   h[1].DeltaT,
+			Recalled: h[1].Rating > Again,
+		})
+	}
+	return out
+}
+
+// PretrainInitialStability fits the initial stabilities W[0..3] from the
+// recall observed after each first rating.
+//
+// For every rating with at least 10 samples the stability is chosen to
+// minimize the log loss of the default forgetting curve, smoothed towards
+// the default weight by a penalty that fades as the sample count grows.
+// The fitted values are then made non-decreasing from Again to Easy, letting
+// the better-sampled rating win any conflict. Ratings with too few samples
+// are inferred from their fitted neighbours, keeping the ratios between the
+// default weights. Returns ErrNotEnoughData when no rating has enough samples.
+func PretrainInitialStability(pairs []FirstReview) ([4]float64, error) {
+	defaults := DefaultWeights()
+	p := DefaultParam()
+	decay, factor := p.decayAndFactor()
+
+	type group struct {
+		deltaT   float64
+		count    float64
+		recalled float64
+	}
+	groups := map[Rating]map[float64]*group{}
+	counts := map[Rating]int{}
+	for _, pair := range pairs {
+		if pair.Rating < Again || pair.Rating > Easy || !isFinite(pair.DeltaT) || pair.DeltaT <= 0 {
+			continue
+		}
+		if groups[pair.Rating] == nil {
+			groups[pair.Rating] = map[float64]*group{}
+		}
+		g := groups[pair.Rating][pair.DeltaT]
+		if g == nil {
+			g = &group{deltaT: pair.DeltaT}
+			groups[pair.Rating][pair.DeltaT] = g
+		}
+		g.count++
+		if pair.Recalled {
+			g.recalled++
+		}
+		counts[pair.Rating]++
+	}
+
+	fitted := map[Rating]float64{}
+	for rating, byDelta := range groups {
+		n := counts[rating]
+		if n < pretrainMinSamples {
+			continue
+		}
+		prior := math.Log(defaults[rating-1])
+		objective := func(logS float64) float64 {
+			s := math.Exp(logS)
+			loss := 0.0
+			for _, g := range byDelta {
+				r := clamp(forgettingCurve(g.deltaT, s, decay, factor), minProbability, 1-minProbability)
+				loss -= g.recalled*math.Log(r) + (g.count-g.recalled)*math.Log(1-r)
+			}
+			return loss/float64(n) + math.Abs(logS-prior)/float64(n)
+		}
+		fitted[rating] = math.Exp(goldenSectionMin(objective, math.Log(pretrainSMin), math.Log(pretrainSMax)))
+	}
+	if len(fitted) == 0 {
+		return [4]float64{}, ErrNotEnoughData
+	}
+
+	ratings := make([]Rating, 0, len(fitted))
+	for r := range fitted {
+		ratings = append(ratings, r)
+	}
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i] < ratings[j] })
+	for i := range ratings {
+		for j := i + 1; j < len(ratings); j++ {
+			small, big := ratings[i], ratings[j]
+			if fitted[small] <= fitted[big] {
+				continue
+			}
+			if counts[small] > counts[big] {
+				fitted[big] = fitted[small]
+			} else {
+				fitted[small] = fitted[big]
+			}
+		}
+	}
+
+	var out [4]float64
+	for r := Again; r <= Easy; r++ {
+		if s, ok := fitted[r]; ok {
+			out[r-1] = s
+			continue
+		}
+		lower, upper := Rating(0), Rating(0)
+		for _, fr := range ratings {
+			if fr < r {
+				lower = fr
+			} else if upper == 0 {
+				upper = fr
+			}
+		}
+		switch {
+		case lower != 0 && upper != 0:
+			dl, du, dr := math.Log(defaults[lower-1]), math.Log(defaults[upper-1]), math.Log(defaults[r-1])
+			sl, su := math.Log(fitted[lower]), math.Log(fitted[upper])
+			out[r-1] = math.Exp(sl + (dr-dl)/(du-dl)*(su-sl))
+		case lower != 0:
+			out[r-1] = fitted[lower] * defaults[r-1] / defaults[lower-1]
+		default:
+			out[r-1] = fitted[upper] * defaults[r-1] / defaults[upper-1]
+		}
+	}
+	for i := range out {
+		out[i] = clamp(out[i], pretrainSMin, pretrainSMax)
+	}
+	return out, nil
+}
+
+// goldenSectionMin returns the argument in [lo, hi] minimizing the unimodal
+// function f.
+func goldenSectionMin(f func(float64) float64, lo, hi float64) float64 {
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := lo, hi
+	c := b - invPhi*(b-a)
+	d := a + invPhi*(b-a)
+	fc, fd := f(c), f(d)
+	for b-a > 1e-6 {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - invPhi*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + invPhi*(b-a)
+			fd = f(d)
+		}
+	}
+	return (a + b) / 2
+}
@@ -0,0 +1,185 @@
+package fsrs
+
+import (
+	"sort"
+	"time"
+)
+
+// QueueOrder selects how due review cards are sorted in a study session.
+type QueueOrder int8
+
+const (
+	// OrderDue shows the earliest due cards first.
+	OrderDue QueueOrder = iota
+	// OrderRetrievability shows the cards most likely to be forgotten first.
+	OrderRetrievability
+	// OrderOverdueness shows first the cards that are most overdue relative
+	// to their scheduled interval.
+	OrderOverdueness
+	// OrderDifficulty shows the most difficult cards first.
+	OrderDifficulty
+	// OrderRandom shuffles the cards using QueueOptions.Seed.
+	OrderRandom
+)
+
+func (o QueueOrder) String() string {
+	switch o {
+	case OrderDue:
+		return "Due"
+	case OrderRetrievability:
+		return "Retrievability"
+	case OrderOverdueness:
+		return "Overdueness"
+	case OrderDifficulty:
+		return "Difficulty"
+	case OrderRandom:
+		return "Random"
+	}
+	return "unknown"
+}
+
+// QueueOptions configures a [Queue]. A negative limit means no limit and 0
+// means none, so a daily budget that is used up leaves its cards out.
+type QueueOptions struct {
+	NewPerDay     int `json:"NewPerDay"`
+	ReviewsPerDay int `json:"ReviewsPerDay"`
+	// LearnAhead includes Learning and Relearning cards that become due
+	// within this window after now.
+	LearnAhead time.Duration `json:"LearnAhead"`
+	Order      QueueOrder    `json:"Order"`
+	Seed       int           `json:"Seed"`
+	// BurySiblings leaves out Review and New cards whose sibling (a card
+	// with the same non-zero NoteID) was reviewed earlier in the current
+	// review day or comes earlier in the session.
+	BurySiblings bool `json:"BurySiblings"`
+}
+
+// DefaultQueueOptions returns Anki's defaults: 20 new cards and 200 reviews a
+// day, learning 20 minutes ahead, with reviews in due order.
+func DefaultQueueOptions() QueueOptions {
+	return QueueOptions{
+		NewPerDay:     20,
+		ReviewsPerDay: 200,
+		LearnAhead:    20 * time.Minute,
+		Order:         OrderDue,
+	}
+}
+
+// QueueItem is a card selected for a study session. Index is its position in
+// the slice passed to [Queue.Build].
+type QueueItem struct {
+	Index int  `json:"Index"`
+	Card  Card `json:"Card"`
+}
+
+// Queue decides which cards to study now and in what order.
+type Queue struct {
+	fsrs *FSRS
+	opts QueueOptions
+}
+
+// NewQueue creates a Queue that uses f for retrievability and day boundaries.
+func NewQueue(f *FSRS, opts QueueOptions) *Queue {
+	return &Queue{fsrs: f, opts: opts}
+}
+
+// Build returns the study session for now. Suspended cards are left out and
+// the rest are listed in this order: Learning and Relearning cards due within
+// LearnAhead, earliest first; Review cards due on or before the current
+// review day, sorted by Order and capped at ReviewsPerDay; and New cards in
+// input order, capped at NewPerDay.
+// Limits apply to the returned session only; callers that already studied
+// today should lower them accordingly.
+func (q *Queue) Build(cards []Card, now time.Time) []QueueItem {
+	var learning, review, fresh []QueueItem
+	today := q.fsrs.reviewDay(now)
+	studied := map[int64]bool{}
+	for i, card := range cards {
+		if card.NoteID != 0 && !card.LastReview.IsZero() && q.fsrs.reviewDay(card.LastReview).Equal(today) {
+			studied[card.NoteID] = true
+		}
+		if card.Suspended {
+			continue
+		}
+		item := QueueItem{Index: i, Card: card}
+		switch card.State {
+		case New:
+			fresh = append(fresh, item)
+		case Learning, Relearning:
+			if !card.Due.After(now.Add(q.opts.LearnAhead)) {
+				learning = append(learning, item)
+			}
+		case Review:
+			if !q.fsrs.reviewDay(card.Due).After(today) {
+				review = append(review, item)
+			}
+		}
+	}
+
+	sort.SliceStable(learning, func(i, j int) bool { return learning[i].Card.Due.Before(learning[j].Card.Due) })
+	q.sortReviews(review, now)
+	if q.opts.BurySiblings {
+		review = burySiblings(review, studied)
+		fresh = burySiblings(fresh, studied)
+	}
+	review = limitItems(review, q.opts.ReviewsPerDay)
+	fresh = limitItems(fresh, q.opts.NewPerDay)
+
+	session := make([]QueueItem, 0, len(learning)+len(review)+len(fresh))
+	session = append(session, learning...)
+	session = append(session, review...)
+	return append(session, fresh...)
+}
+
+func (q *Queue) sortReviews(items []QueueItem, now time.Time) {
+	switch q.opts.Order {
+	case OrderRetrievability:
+		r := make(map[int]float64, len(items))
+		for _, item := range items {
+			r[item.Index], _ = q.fsrs.Retrievability(item.Card, now)
+		}
+		sort.SliceStable(items, func(i, j int) bool { return r[items[i].Index] < r[items[j].Index] })
+	case OrderOverdueness:
+		sort.SliceStable(items, func(i, j int) bool { return overdueness(items[i].Card, now) > overdueness(items[j].Card, now) })
+	case OrderDifficulty:
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Card.Difficulty > items[j].Card.Difficulty })
+	case OrderRandom:
+		rng := Alea(q.opts.Seed)
+		for i := len(items) - 1; i > 0; i-- {
+			j := int(rng() * float64(i+1))
+			items[i], items[j] = items[j], items[i]
+		}
+	default:
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Card.Due.Before(items[j].Card.Due) })
+	}
+}
+
+// overdueness is the time past due divided by the scheduled interval.
+func overdueness(card Card, now time.Time) float64 {
+	interval := float64(max(card.ScheduledDays, 1))
+	return now.Sub(card.Due).Hours() / 24 / interval
+}
+
+// burySiblings drops the items whose note is in seen and adds the notes of
+// the kept items to it, so only the first card of each note survives.
+func burySiblings(items []QueueItem, seen map[int64]bool) []QueueItem {
+	kept := items[:0]
+	for _, item := range items {
+		note := item.Card.NoteID
+		if note != 0 && seen[note] {
+			continue
+		}
+		if note != 0 {
+			seen[note] = true
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+func limitItems(items []QueueItem, limit int) []QueueItem {
+	if limit >= 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
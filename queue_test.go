@@ -0,0 +1,114 @@
+package fsrs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueueBuild(t *testing.T) {
+	now := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	cards := []Card{
+		0: NewCard(now),
+		1: {State: Review, Due: now.Add(-2 * day), ScheduledDays: 10, Stability: 10, Difficulty: 3, LastReview: now.Add(-12 * day)},
+		2: {State: Learning, Due: now.Add(10 * time.Minute), Stability: 1, Difficulty: 5, LastReview: now.Add(-time.Minute)},
+		3: {State: Review, Due: now.Add(-1 * day), ScheduledDays: 2, Stability: 2, Difficulty: 8, LastReview: now.Add(-3 * day)},
+		4: {State: Review, Due: now.Add(5 * day), ScheduledDays: 6, Stability: 6, Difficulty: 5, LastReview: now.Add(-day)},
+		5: {State: Relearning, Due: now.Add(time.Hour), Stability: 1, Difficulty: 7, LastReview: now.Add(-time.Minute)},
+		6: NewCard(now),
+		7: {State: Review, Due: now.Add(6 * time.Hour), ScheduledDays: 30, Stability: 30, Difficulty: 4, LastReview: now.Add(-30 * day)},
+	}
+	f := NewFSRS(DefaultParam())
+
+	indices := func(items []QueueItem) []int {
+		out := make([]int, len(items))
+		for i, item := range items {
+			out[i] = item.Index
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		opts func(*QueueOptions)
+		want []int
+	}{
+		{"due order", func(o *QueueOptions) {}, []int{2, 1, 3, 7, 0, 6}},
+		{"retrievability order", func(o *QueueOptions) { o.Order = OrderRetrievability }, []int{2, 3, 1, 7, 0, 6}},
+		{"overdueness order", func(o *QueueOptions) { o.Order = OrderOverdueness }, []int{2, 3, 1, 7, 0, 6}},
+		{"difficulty order", func(o *QueueOptions) { o.Order = OrderDifficulty }, []int{2, 3, 7, 1, 0, 6}},
+		{"limits", func(o *QueueOptions) { o.ReviewsPerDay = 1; o.NewPerDay = 1 }, []int{2, 1, 0}},
+		{"learn ahead", func(o *QueueOptions) { o.LearnAhead = 2 * time.Hour; o.NewPerDay = 0 }, []int{2, 5, 1, 3, 7}},
+		{"unlimited", func(o *QueueOptions) { o.ReviewsPerDay = -1; o.NewPerDay = -1 }, []int{2, 1, 3, 7, 0, 6}},
+		{"used up budgets", func(o *QueueOptions) { o.ReviewsPerDay = 0; o.NewPerDay = 0 }, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultQueueOptions()
+			tt.opts(&opts)
+			got := indices(NewQueue(f, opts).Build(cards, now))
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got=%v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got=%v", tt.want, got)
+				}
+			}
+		})
+	}
+
+	t.Run("random order is seeded", func(t *testing.T) {
+		opts := DefaultQueueOptions()
+		opts.Order = OrderRandom
+		opts.Seed = 7
+		first := indices(NewQueue(f, opts).Build(cards, now))
+		second := indices(NewQueue(f, opts).Build(cards, now))
+		if len(first) != 6 {
+			t.Fatalf("expected 6 cards, got=%v", first)
+		}
+		for i := range first {
+			if first[i] != second[i] {
+				t.Fatalf("expected the same order for the same seed: %v vs %v", first, second)
+			}
+		}
+	})
+}
+
+func TestQueueBurySiblings(t *testing.T) {
+	now := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	cards := []Card{
+		0: {ID: 1, NoteID: 10, State: Review, Due: now.Add(-day), ScheduledDays: 5, Stability: 5, Difficulty: 5, LastReview: now.Add(-6 * day)},
+		1: {ID: 2, NoteID: 10, State: Review, Due: now.Add(-2 * day), ScheduledDays: 5, Stability: 5, Difficulty: 5, LastReview: now.Add(-7 * day)},
+		2: {ID: 3, NoteID: 20, State: Review, Due: now.Add(-day), ScheduledDays: 5, Stability: 5, Difficulty: 5, LastReview: now.Add(-6 * day)},
+		3: {ID: 4, NoteID: 20, State: Review, Due: now.Add(4 * day), ScheduledDays: 4, Stability: 4, Difficulty: 5, LastReview: now.Add(-2 * time.Hour)},
+		4: {ID: 5, NoteID: 30, State: New, Due: now},
+		5: {ID: 6, NoteID: 30, State: New, Due: now},
+		6: {ID: 7, State: New, Due: now},
+		7: {ID: 8, State: New, Due: now},
+	}
+	f := NewFSRS(DefaultParam())
+	opts := DefaultQueueOptions()
+
+	count := len(NewQueue(f, opts).Build(cards, now))
+	if count != 7 {
+		t.Fatalf("expected 7 cards without burying, got=%d", count)
+	}
+
+	opts.BurySiblings = true
+	items := NewQueue(f, opts).Build(cards, now)
+	var got []int
+	for _, item := range items {
+		got = append(got, item.Index)
+	}
+	want := []int{1, 4, 6, 7}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got=%v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got=%v", want, got)
+		}
+	}
+}
@@ -40,7 +40,10 @@ func (f *FSRS) Reschedule(card Card, reviews []ReviewHistory, opts RescheduleOpt
 	} else {
 		startDue = card.Due
 	}
-	curCard := Card{Due: startDue}
+	curCard := resetCard(card, startDue)
+	if opts.DesiredRetention != 0 {
+		curCard.DesiredRetention = opts.DesiredRetention
+	}
 
 	collections := make([]SchedulingInfo, 0, len(working))
 	for _, review := range working {
@@ -57,6 +60,9 @@ func (f *FSRS) Reschedule(card Card, reviews []ReviewHistory, opts RescheduleOpt
 				return RescheduleResult{}, err
 			}
 		} else {
+			// A card suspended as a leech during replay was evidently
+			// unsuspended before it was reviewed again.
+			curCard.Suspended = false
 			item, err = f.Next(curCard, review.Review, review.Rating)
 			if err != nil {
 				return RescheduleResult{}, err
@@ -69,8 +75,12 @@ func (f *FSRS) Reschedule(card Card, reviews []ReviewHistory, opts RescheduleOpt
 
 	var rescheduleItem *SchedulingInfo
 	if len(collections) > 0 {
+		now := opts.Now
+		if now.IsZero() {
+			now = f.Now()
+		}
 		var err2 error
-		rescheduleItem, err2 = f.calculateManualRecord(card, opts.Now, collections[len(collections)-1], opts.UpdateMemoryState)
+		rescheduleItem, err2 = f.calculateManualRecord(card, now, collections[len(collections)-1], opts.UpdateMemoryState)
 		if err2 != nil {
 			return RescheduleResult{}, err2
 		}
@@ -98,9 +108,13 @@ func (f *FSRS) handleManualRating(card Card, state State, reviewed time.Time, st
 			RemainingSteps: card.RemainingSteps,
 			Review:         reviewed,
 		}
-		nextCard := Card{Due: effectiveDue, LastReview: reviewed}
+		// Like Forget, a replayed reset keeps the leech flags.
+		nextCard := resetCard(card, effectiveDue)
+		nextCard.LastReview = reviewed
+		nextCard.Leech = card.Leech
+		nextCard.Suspended = card.Suspended
 		if effectiveDue.After(reviewed) {
-			nextCard.ScheduledDays = dateDiffInDays(reviewed, effectiveDue)
+			nextCard.ScheduledDays = f.daysBetween(reviewed, effectiveDue)
 		}
 		return SchedulingInfo{Card: nextCard, ReviewLog: log}, nil
 	}
@@ -109,7 +123,7 @@ func (f *FSRS) handleManualRating(card Card, state State, reviewed time.Time, st
 		return SchedulingInfo{}, ErrManualDueRequired
 	}
 
-	scheduledDays := dateDiffInDays(reviewed, due)
+	scheduledDays := f.daysBetween(reviewed, due)
 
 	logDue := card.LastReview
 	if logDue.IsZero() {
@@ -154,10 +168,11 @@ func (f *FSRS) calculateManualRecord(currentCard Card, now time.Time, lastItem S
 		return nil, nil
 	}
 
-	scheduledDays := dateDiffInDays(currentCard.Due, rescheduleCard.Due)
+	scheduledDays := f.daysBetween(currentCard.Due, rescheduleCard.Due)
 
 	curCard := currentCard
 	curCard.ScheduledDays = scheduledDays
+	curCard.DesiredRetention = rescheduleCard.DesiredRetention
 
 	var stab, diff float64
 	if updateMemory {
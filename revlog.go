@@ -0,0 +1,216 @@
+package fsrs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// revlogHeader is the column layout of the FSRS benchmark and fsrs-rs review
+// logs. review_time and review_duration are in milliseconds.
+var revlogHeader = []string{"card_id", "review_time", "review_rating", "review_state", "review_duration"}
+
+// RevlogEntry is one row of a review log in the FSRS benchmark CSV layout.
+// State is the card's state before the review and Rating is Manual for
+// entries that were not graded, such as a reset.
+type RevlogEntry struct {
+	CardID   int64         `json:"CardID"`
+	Review   time.Time     `json:"Review"`
+	Rating   Rating        `json:"Rating"`
+	State    State         `json:"State"`
+	Duration time.Duration `json:"Duration"`
+}
+
+// NewRevlogEntry returns the entry for log, a review of the card with the
+// given ID. The duration is not tracked by ReviewLog and is left zero.
+func NewRevlogEntry(cardID int64, log ReviewLog) RevlogEntry {
+	return RevlogEntry{CardID: cardID, Review: log.Review, Rating: log.Rating, State: log.State}
+}
+
+// RevlogWriter writes review logs in the benchmark CSV layout, starting with a
+// header row.
+type RevlogWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewRevlogWriter returns a RevlogWriter writing to w. Call Flush when done.
+func NewRevlogWriter(w io.Writer) *RevlogWriter {
+	return &RevlogWriter{w: csv.NewWriter(w)}
+}
+
+// Write writes one entry, preceded by the header on the first call.
+func (rw *RevlogWriter) Write(e RevlogEntry) error {
+	if !rw.wroteHeader {
+		if err := rw.w.Write(revlogHeader); err != nil {
+			return err
+		}
+		rw.wroteHeader = true
+	}
+	return rw.w.Write([]string{
+		strconv.FormatInt(e.CardID, 10),
+		strconv.FormatInt(e.Review.UnixMilli(), 10),
+		strconv.Itoa(int(e.Rating)),
+		strconv.Itoa(int(e.State)),
+		strconv.FormatInt(e.Duration.Milliseconds(), 10),
+	})
+}
+
+// WriteLog writes log, a review of the card with the given ID.
+func (rw *RevlogWriter) WriteLog(cardID int64, log ReviewLog) error {
+	return rw.Write(NewRevlogEntry(cardID, log))
+}
+
+// Flush writes any buffered data and reports any error from earlier writes.
+// An empty log still gets its header.
+func (rw *RevlogWriter) Flush() error {
+	if !rw.wroteHeader {
+		if err := rw.w.Write(revlogHeader); err != nil {
+			return err
+		}
+		rw.wroteHeader = true
+	}
+	rw.w.Flush()
+	return rw.w.Error()
+}
+
+// RevlogReader reads review logs in the benchmark CSV layout. Columns are
+// matched by the names in the header row, so their order does not matter and
+// unknown columns are ignored. card_id, review_time and review_rating are
+// required; review_state and review_duration default to zero when absent.
+type RevlogReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+// NewRevlogReader returns a RevlogReader reading from r.
+func NewRevlogReader(r io.Reader) *RevlogReader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return &RevlogReader{r: cr}
+}
+
+// Read returns the next entry, or io.EOF at the end of the input.
+func (rr *RevlogReader) Read() (RevlogEntry, error) {
+	if rr.columns == nil {
+		if err := rr.readHeader(); err != nil {
+			return RevlogEntry{}, err
+		}
+	}
+	record, err := rr.r.Read()
+	if err != nil {
+		return RevlogEntry{}, err
+	}
+	line, _ := rr.r.FieldPos(0)
+	field := func(name string) (int64, error) {
+		i, ok := rr.columns[name]
+		if !ok {
+			return 0, nil
+		}
+		if i >= len(record) {
+			return 0, revlogError(line, "missing %s", name)
+		}
+		v, err := strconv.ParseInt(record[i], 10, 64)
+		if err != nil {
+			return 0, revlogError(line, "invalid %s %q", name, record[i])
+		}
+		return v, nil
+	}
+
+	var values [5]int64
+	for i, name := range revlogHeader {
+		if values[i], err = field(name); err != nil {
+			return RevlogEntry{}, err
+		}
+	}
+	e := RevlogEntry{
+		CardID:   values[0],
+		Review:   time.UnixMilli(values[1]).UTC(),
+		Rating:   Rating(values[2]),
+		State:    State(values[3]),
+		Duration: time.Duration(values[4]) * time.Millisecond,
+	}
+	if e.Rating < Manual || e.Rating > Easy {
+		return RevlogEntry{}, revlogError(line, "invalid review_rating %d", e.Rating)
+	}
+	if !isValidState(e.State) {
+		return RevlogEntry{}, revlogError(line, "invalid review_state %d", e.State)
+	}
+	return e, nil
+}
+
+// ReadAll reads the remaining entries.
+func (rr *RevlogReader) ReadAll() ([]RevlogEntry, error) {
+	var entries []RevlogEntry
+	for {
+		e, err := rr.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+func (rr *RevlogReader) readHeader() error {
+	header, err := rr.r.Read()
+	if err != nil {
+		return err
+	}
+	rr.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		rr.columns[name] = i
+	}
+	for _, name := range revlogHeader[:3] {
+		if _, ok := rr.columns[name]; !ok {
+			return revlogError(1, "missing column %s", name)
+		}
+	}
+	return nil
+}
+
+func revlogError(line int, format string, args ...any) error {
+	return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: revlog line %d: ", line) + fmt.Sprintf(format, args...)}
+}
+
+// RevlogHistories groups entries by card and rebuilds each card's
+// ReviewEntries in review time order, with DeltaT counted in review days as
+// [FSRS.Next] does, so Parameters.Location and DayStartHour apply. A Manual
+// entry in the New state marks a reset and starts the history over; other
+// Manual entries are skipped. Cards without graded reviews are left out.
+func (f *FSRS) RevlogHistories(entries []RevlogEntry) map[int64]ReviewEntries {
+	byCard := map[int64][]RevlogEntry{}
+	for _, e := range entries {
+		byCard[e.CardID] = append(byCard[e.CardID], e)
+	}
+	histories := make(map[int64]ReviewEntries, len(byCard))
+	for id, card := range byCard {
+		sort.SliceStable(card, func(i, j int) bool { return card[i].Review.Before(card[j].Review) })
+		var history ReviewEntries
+		var last time.Time
+		for _, e := range card {
+			if e.Rating == Manual {
+				if e.State == New {
+					history, last = nil, time.Time{}
+				}
+				continue
+			}
+			deltaT := 0.0
+			if !last.IsZero() {
+				deltaT = float64(f.daysBetween(last, e.Review))
+			}
+			history = append(history, ReviewEntry{Rating: e.Rating, DeltaT: deltaT})
+			last = e.Review
+		}
+		if len(history) > 0 {
+			histories[id] = history
+		}
+	}
+	return histories
+}
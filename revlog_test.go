@@ -0,0 +1,90 @@
+package fsrs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRevlogRoundTrip(t *testing.T) {
+	f := NewFSRS(DefaultParam())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	card := NewCard(now)
+	var want []RevlogEntry
+	var buf bytes.Buffer
+	w := NewRevlogWriter(&buf)
+	for _, rating := range []Rating{Good, Good, Again, Good} {
+		info, err := f.Next(card, now, rating)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteLog(42, info.ReviewLog); err != nil {
+			t.Fatalf("WriteLog returned error: %v", err)
+		}
+		want = append(want, NewRevlogEntry(42, info.ReviewLog))
+		card, now = info.Card, info.Card.Due
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "card_id,review_time,review_rating,review_state,review_duration\n") {
+		t.Errorf("expected the benchmark header, got=%q", buf.String())
+	}
+
+	got, err := NewRevlogReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got=%+v\nwant=%+v", got, want)
+	}
+}
+
+func TestRevlogReader(t *testing.T) {
+	input := "review_rating,card_id,extra,review_time\n3,1,x,1719824400000\n"
+	got, err := NewRevlogReader(strings.NewReader(input)).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+	want := []RevlogEntry{{CardID: 1, Review: time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC), Rating: Good}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got=%+v want=%+v", got, want)
+	}
+
+	for _, input := range []string{
+		"card_id,review_time\n1,0\n",
+		"card_id,review_time,review_rating\n1,0,5\n",
+		"card_id,review_time,review_rating\n1,abc,3\n",
+	} {
+		if _, err := NewRevlogReader(strings.NewReader(input)).ReadAll(); !errors.Is(err, &Error{Code: ErrCodeInvalidInput}) {
+			t.Errorf("expected ErrCodeInvalidInput for %q, got=%v", input, err)
+		}
+	}
+}
+
+func TestRevlogHistories(t *testing.T) {
+	p := DefaultParam()
+	p.DayStartHour = 4
+	f := NewFSRS(p)
+	day := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	entries := []RevlogEntry{
+		{CardID: 1, Review: day.Add(24*time.Hour + 3*time.Hour), Rating: Good, State: Learning},
+		{CardID: 1, Review: day.Add(10 * time.Hour), Rating: Good, State: New},
+		{CardID: 1, Review: day.Add(72*time.Hour + 5*time.Hour), Rating: Again, State: Review},
+		{CardID: 2, Review: day, Rating: Easy, State: New},
+		{CardID: 2, Review: day.Add(48 * time.Hour), Rating: Manual, State: New},
+		{CardID: 2, Review: day.Add(72 * time.Hour), Rating: Good, State: New},
+		{CardID: 3, Review: day, Rating: Manual, State: Review},
+	}
+	got := f.RevlogHistories(entries)
+	want := map[int64]ReviewEntries{
+		1: {{Rating: Good, DeltaT: 0}, {Rating: Good, DeltaT: 0}, {Rating: Again, DeltaT: 3}},
+		2: {{Rating: Good, DeltaT: 0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got=%+v want=%+v", got, want)
+	}
+}
@@ -5,6 +5,8 @@ import "time"
 // Rollback reverts a card to its pre-review state using the information stored
 // in the ReviewLog. It returns an error if the log entry is a Manual rating
 // (ErrManualRating) or if the rating is outside [Again, Easy] (ErrInvalidRating).
+// Rolling back the review that made the card a leech also lifts the
+// suspension it caused.
 func (f *FSRS) Rollback(card Card, log ReviewLog) (Card, error) {
 	if log.Rating == Manual {
 		return Card{}, ErrManualRating
@@ -34,5 +36,9 @@ func (f *FSRS) Rollback(card Card, log ReviewLog) (Card, error) {
 			result.Lapses = card.Lapses - 1
 		}
 	}
+	if log.Leech {
+		result.Suspended = false
+		result.Leech = f.LeechThreshold > 0 && result.Lapses >= f.LeechThreshold
+	}
 	return result, nil
 }
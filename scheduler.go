@@ -1,7 +1,6 @@
 package fsrs
 
 import (
-	"fmt"
 	"time"
 )
 
@@ -13,15 +12,26 @@ type Scheduler struct {
 	now     time.Time
 	next    RecordLog
 
-	impl implScheduler
+	newImpl StrategyFunc
+	impl    SchedulerStrategy
 }
 
-type implScheduler interface {
-	newState(Rating) SchedulingInfo
-	learningState(Rating) SchedulingInfo
-	reviewState(Rating) SchedulingInfo
+// SchedulerStrategy computes the result of reviewing the Scheduler's card
+// with a given grade. Review calls NewState, LearningState or ReviewState
+// depending on the card's state before the review, and Preview calls it
+// once per grade. Implementations are built by a [StrategyFunc] around the
+// Scheduler whose accessors describe the review, and may wrap
+// [BasicStrategy] or [LongTermStrategy].
+type SchedulerStrategy interface {
+	NewState(grade Rating) SchedulingInfo
+	LearningState(grade Rating) SchedulingInfo
+	ReviewState(grade Rating) SchedulingInfo
 }
 
+// StrategyFunc builds the SchedulerStrategy for a Scheduler. It is called
+// for every review, so the strategy may keep per-review state.
+type StrategyFunc func(s *Scheduler) SchedulerStrategy
+
 func (s *Scheduler) Preview() RecordLog {
 	return RecordLog{
 		Again: s.Review(Again),
@@ -36,20 +46,42 @@ func (s *Scheduler) Review(grade Rating) SchedulingInfo {
 	var item SchedulingInfo
 	switch cardState {
 	case New:
-		item = s.impl.newState(grade)
+		item = s.impl.NewState(grade)
 	case Learning, Relearning:
-		item = s.impl.learningState(grade)
+		item = s.impl.LearningState(grade)
 	case Review:
-		item = s.impl.reviewState(grade)
+		item = s.impl.ReviewState(grade)
 	}
 	return item
 }
 
+// Parameters returns the Scheduler's own copy of the parameters.
+func (s *Scheduler) Parameters() *Parameters { return s.parameters }
+
+// Last returns the card as it was before the review.
+func (s *Scheduler) Last() Card { return s.last }
+
+// Current returns the starting point of every result: the card before the
+// review with LastReview set to the review time and Reps incremented.
+func (s *Scheduler) Current() Card { return s.current }
+
+// Now returns the review time.
+func (s *Scheduler) Now() time.Time { return s.now }
+
+// ElapsedDays returns the review days since the card's last review, or 0
+// for a New card.
+func (s *Scheduler) ElapsedDays() float64 { return s.elapsedDays() }
+
+// BuildLog returns the review log of the review with the given rating.
+func (s *Scheduler) BuildLog(rating Rating) ReviewLog { return s.buildLog(rating) }
+
 func (s *Scheduler) initSeed() {
-	t := s.now
-	reps := s.current.Reps
-	mul := s.current.Difficulty * s.current.Stability
-	s.parameters.seed = fmt.Sprintf("%d_%d_%f", t.UnixMilli(), reps, mul)
+	seedStrategy := s.parameters.SeedStrategy
+	if seedStrategy == nil {
+		seedStrategy = DefaultSeedStrategy
+	}
+	s.parameters.seed = seedStrategy(s.current, s.now)
+	s.parameters.reviewTime = s.now
 }
 
 func (s *Scheduler) buildLog(rating Rating) ReviewLog {
@@ -58,38 +90,53 @@ func (s *Scheduler) buildLog(rating Rating) ReviewLog {
 		due = s.last.LastReview
 	}
 	return ReviewLog{
-		Rating:         rating,
-		Due:            due,
-		ScheduledDays:  s.current.ScheduledDays,
-		Review:         s.now,
-		State:          s.current.State,
-		Stability:      s.current.Stability,
-		Difficulty:     s.current.Difficulty,
-		RemainingSteps: s.current.RemainingSteps,
+		Rating:           rating,
+		Due:              due,
+		ScheduledDays:    s.current.ScheduledDays,
+		Review:           s.now,
+		State:            s.current.State,
+		Stability:        s.current.Stability,
+		Difficulty:       s.current.Difficulty,
+		RemainingSteps:   s.current.RemainingSteps,
+		DesiredRetention: s.parameters.RequestRetention,
 	}
 }
 
-func (p *Parameters) newScheduler(card Card, now time.Time, newImpl func(s *Scheduler) implScheduler) *Scheduler {
-	localParams := *p
-
+// NewScheduler creates a Scheduler for the given card and reference time
+// whose results are computed by the strategy newStrategy builds.
+func (p *Parameters) NewScheduler(card Card, now time.Time, newStrategy StrategyFunc) *Scheduler {
 	s := &Scheduler{
-		parameters: &localParams,
+		parameters: new(Parameters),
 		next:       make(RecordLog),
-		last:       card,
-		current:    card,
-		now:        now,
+		newImpl:    newStrategy,
 	}
+	s.reset(p, card, now)
+	return s
+}
+
+// reset prepares s to schedule card at now with a fresh copy of p, so one
+// Scheduler can serve many reviews without allocating.
+func (s *Scheduler) reset(p *Parameters, card Card, now time.Time) {
+	*s.parameters = *p
+	clear(s.next)
+	s.last = card
+	s.current = card
+	s.now = now
 
 	s.current.LastReview = s.now
 	s.current.Reps++
 	s.initSeed()
-
-	s.impl = newImpl(s)
-
-	return s
+	if card.DesiredRetention > 0 {
+		s.parameters.RequestRetention = card.DesiredRetention
+	}
+	s.parameters.prepareExam(card, now)
+	s.impl = s.newImpl(s)
 }
 
 func (p *Parameters) scheduler(card Card, now time.Time) *Scheduler {
+	if p.Strategy != nil {
+		return p.NewScheduler(card, now, p.Strategy)
+	}
 	if p.EnableShortTerm {
 		return p.NewBasicScheduler(card, now)
 	} else {
@@ -115,5 +162,5 @@ func (s *Scheduler) elapsedDays() float64 {
 	if s.last.State == New || s.last.LastReview.IsZero() {
 		return 0
 	}
-	return float64(dateDiffInDays(s.last.LastReview, s.now))
+	return float64(s.parameters.daysBetween(s.last.LastReview, s.now))
 }
@@ -9,15 +9,19 @@ type basicScheduler struct {
 	*Scheduler
 }
 
-var _ implScheduler = basicScheduler{}
+var _ SchedulerStrategy = basicScheduler{}
 
 // NewBasicScheduler creates a Scheduler using the standard FSRS algorithm
 // for the given card and reference time. Call Preview or Review on the
 // returned Scheduler to obtain scheduling results.
 func (p *Parameters) NewBasicScheduler(card Card, now time.Time) *Scheduler {
-	return p.newScheduler(card, now, func(s *Scheduler) implScheduler {
-		return basicScheduler{s}
-	})
+	return p.NewScheduler(card, now, BasicStrategy)
+}
+
+// BasicStrategy is the built-in strategy used when
+// Parameters.EnableShortTerm is true, with learning steps.
+func BasicStrategy(s *Scheduler) SchedulerStrategy {
+	return basicScheduler{s}
 }
 
 func (bs basicScheduler) applyStep(next *Card, delayMinutes float64, toState State) {
@@ -31,15 +35,16 @@ func (bs basicScheduler) applyStep(next *Card, delayMinutes float64, toState Sta
 	}
 }
 
-func (bs basicScheduler) graduateToReview(next *Card, stability, elapsedDays float64) {
-	interval := bs.parameters.nextInterval(stability, elapsedDays)
+func (bs basicScheduler) graduateToReview(next *Card, grade Rating, stability, elapsedDays float64) {
+	interval := bs.parameters.modifyInterval(bs.parameters.nextInterval(stability, elapsedDays), *next, grade, elapsedDays)
+	interval = bs.parameters.capForExam(interval, stability)
 	next.ScheduledDays = uint64(interval)
-	next.Due = bs.now.Add(daysToDuration(interval, bs.parameters.MaximumInterval))
+	next.Due = bs.parameters.addDays(bs.now, interval)
 	next.State = Review
 	next.RemainingSteps = 0
 }
 
-func (bs basicScheduler) newState(grade Rating) SchedulingInfo {
+func (bs basicScheduler) NewState(grade Rating) SchedulingInfo {
 	exist, ok := bs.next[grade]
 	if ok {
 		return exist
@@ -54,14 +59,14 @@ func (bs basicScheduler) newState(grade Rating) SchedulingInfo {
 	switch grade {
 	case Again:
 		if len(steps) == 0 {
-			bs.graduateToReview(&next, next.Stability, elapsed)
+			bs.graduateToReview(&next, grade, next.Stability, elapsed)
 		} else {
 			next.RemainingSteps = len(steps)
 			bs.applyStep(&next, againDelayMinutes(steps), Learning)
 		}
 	case Hard:
 		if len(steps) == 0 {
-			bs.graduateToReview(&next, next.Stability, elapsed)
+			bs.graduateToReview(&next, grade, next.Stability, elapsed)
 		} else {
 			next.RemainingSteps = len(steps)
 			bs.applyStep(&next, hardDelayMinutes(steps), Learning)
@@ -71,10 +76,10 @@ func (bs basicScheduler) newState(grade Rating) SchedulingInfo {
 			next.RemainingSteps = len(steps) - 1
 			bs.applyStep(&next, delay, Learning)
 		} else {
-			bs.graduateToReview(&next, next.Stability, elapsed)
+			bs.graduateToReview(&next, grade, next.Stability, elapsed)
 		}
 	case Easy:
-		bs.graduateToReview(&next, next.Stability, elapsed)
+		bs.graduateToReview(&next, grade, next.Stability, elapsed)
 	}
 
 	item := SchedulingInfo{
@@ -95,7 +100,7 @@ func (bs basicScheduler) computeLearningStability(grade Rating, elapsedDays floa
 	return bs.parameters.nextRecallStability(bs.last.Difficulty, bs.last.Stability, retrievability, grade)
 }
 
-func (bs basicScheduler) learningState(grade Rating) SchedulingInfo {
+func (bs basicScheduler) LearningState(grade Rating) SchedulingInfo {
 	exist, ok := bs.next[grade]
 	if ok {
 		return exist
@@ -125,14 +130,14 @@ func (bs basicScheduler) learningState(grade Rating) SchedulingInfo {
 	switch grade {
 	case Again:
 		if len(steps) == 0 || remaining <= 0 {
-			bs.graduateToReview(&next, next.Stability, elapsedDays)
+			bs.graduateToReview(&next, grade, next.Stability, elapsedDays)
 		} else {
 			next.RemainingSteps = len(steps)
 			bs.applyStep(&next, againDelayMinutes(steps), toState)
 		}
 	case Hard:
 		if len(steps) == 0 || remaining <= 0 {
-			bs.graduateToReview(&next, next.Stability, elapsedDays)
+			bs.graduateToReview(&next, grade, next.Stability, elapsedDays)
 		} else {
 			bs.applyStep(&next, hardDelayMinutes(steps), toState)
 		}
@@ -141,10 +146,10 @@ func (bs basicScheduler) learningState(grade Rating) SchedulingInfo {
 			next.RemainingSteps = remaining - 1
 			bs.applyStep(&next, delay, toState)
 		} else {
-			bs.graduateToReview(&next, next.Stability, elapsedDays)
+			bs.graduateToReview(&next, grade, next.Stability, elapsedDays)
 		}
 	case Easy:
-		bs.graduateToReview(&next, next.Stability, elapsedDays)
+		bs.graduateToReview(&next, grade, next.Stability, elapsedDays)
 	}
 
 	item := SchedulingInfo{
@@ -155,7 +160,7 @@ func (bs basicScheduler) learningState(grade Rating) SchedulingInfo {
 	return item
 }
 
-func (bs basicScheduler) reviewState(grade Rating) SchedulingInfo {
+func (bs basicScheduler) ReviewState(grade Rating) SchedulingInfo {
 	exist, ok := bs.next[grade]
 	if ok {
 		return exist
@@ -189,30 +194,31 @@ func (bs basicScheduler) reviewState(grade Rating) SchedulingInfo {
 		nextAgain.RemainingSteps = len(relearnSteps)
 		bs.applyStep(&nextAgain, againDelayMinutes(relearnSteps), Relearning)
 	} else {
-		bs.graduateToReview(&nextAgain, nextAgain.Stability, elapsedDays)
+		bs.graduateToReview(&nextAgain, Again, nextAgain.Stability, elapsedDays)
 	}
 
-	hardInterval := bs.parameters.nextInterval(nextHard.Stability, elapsedDays)
-	goodInterval := bs.parameters.nextInterval(nextGood.Stability, elapsedDays)
+	hardInterval := bs.parameters.modifyInterval(bs.parameters.nextInterval(nextHard.Stability, elapsedDays), nextHard, Hard, elapsedDays)
+	goodInterval := bs.parameters.modifyInterval(bs.parameters.nextInterval(nextGood.Stability, elapsedDays), nextGood, Good, elapsedDays)
+	easyInterval := bs.parameters.modifyInterval(bs.parameters.nextInterval(nextEasy.Stability, elapsedDays), nextEasy, Easy, elapsedDays)
 	hardInterval = min(hardInterval, goodInterval)
 	goodInterval = max(goodInterval, hardInterval+1)
-	easyInterval := max(
-		bs.parameters.nextInterval(nextEasy.Stability, elapsedDays),
-		goodInterval+1,
-	)
+	easyInterval = max(easyInterval, goodInterval+1)
+	hardInterval = bs.parameters.capForExam(hardInterval, nextHard.Stability)
+	goodInterval = bs.parameters.capForExam(goodInterval, nextGood.Stability)
+	easyInterval = bs.parameters.capForExam(easyInterval, nextEasy.Stability)
 
 	nextHard.ScheduledDays = uint64(hardInterval)
-	nextHard.Due = bs.now.Add(daysToDuration(hardInterval, bs.parameters.MaximumInterval))
+	nextHard.Due = bs.parameters.addDays(bs.now, hardInterval)
 	nextHard.State = Review
 	nextHard.RemainingSteps = 0
 
 	nextGood.ScheduledDays = uint64(goodInterval)
-	nextGood.Due = bs.now.Add(daysToDuration(goodInterval, bs.parameters.MaximumInterval))
+	nextGood.Due = bs.parameters.addDays(bs.now, goodInterval)
 	nextGood.State = Review
 	nextGood.RemainingSteps = 0
 
 	nextEasy.ScheduledDays = uint64(easyInterval)
-	nextEasy.Due = bs.now.Add(daysToDuration(easyInterval, bs.parameters.MaximumInterval))
+	nextEasy.Due = bs.parameters.addDays(bs.now, easyInterval)
 	nextEasy.State = Review
 	nextEasy.RemainingSteps = 0
 
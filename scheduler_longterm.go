@@ -8,19 +8,23 @@ type longTermScheduler struct {
 	*Scheduler
 }
 
-var _ implScheduler = longTermScheduler{}
+var _ SchedulerStrategy = longTermScheduler{}
 
 // NewLongTermScheduler creates a Scheduler using the long-term FSRS variant
 // for the given card and reference time. This variant is selected when
 // Parameters.EnableShortTerm is false. Call Preview or Review on the returned
 // Scheduler to obtain scheduling results.
 func (p *Parameters) NewLongTermScheduler(card Card, now time.Time) *Scheduler {
-	return p.newScheduler(card, now, func(s *Scheduler) implScheduler {
-		return longTermScheduler{s}
-	})
+	return p.NewScheduler(card, now, LongTermStrategy)
 }
 
-func (lts longTermScheduler) newState(grade Rating) SchedulingInfo {
+// LongTermStrategy is the built-in strategy used when
+// Parameters.EnableShortTerm is false, scheduling every review in days.
+func LongTermStrategy(s *Scheduler) SchedulerStrategy {
+	return longTermScheduler{s}
+}
+
+func (lts longTermScheduler) NewState(grade Rating) SchedulingInfo {
 	exist, ok := lts.next[grade]
 	if ok {
 		return exist
@@ -56,11 +60,11 @@ func (lts longTermScheduler) initDs(nextAgain, nextHard, nextGood, nextEasy *Car
 	nextEasy.Stability = lts.parameters.initStability(Easy)
 }
 
-func (lts longTermScheduler) learningState(grade Rating) SchedulingInfo {
-	return lts.reviewState(grade)
+func (lts longTermScheduler) LearningState(grade Rating) SchedulingInfo {
+	return lts.ReviewState(grade)
 }
 
-func (lts longTermScheduler) reviewState(grade Rating) SchedulingInfo {
+func (lts longTermScheduler) ReviewState(grade Rating) SchedulingInfo {
 	exist, ok := lts.next[grade]
 	if ok {
 		return exist
@@ -86,27 +90,31 @@ func (lts longTermScheduler) reviewState(grade Rating) SchedulingInfo {
 }
 
 func (lts longTermScheduler) nextInterval(nextAgain, nextHard, nextGood, nextEasy *Card, elapsedDays float64) {
-	againInterval := lts.parameters.nextInterval(nextAgain.Stability, elapsedDays)
-	hardInterval := lts.parameters.nextInterval(nextHard.Stability, elapsedDays)
-	goodInterval := lts.parameters.nextInterval(nextGood.Stability, elapsedDays)
-	easyInterval := lts.parameters.nextInterval(nextEasy.Stability, elapsedDays)
+	againInterval := lts.parameters.modifyInterval(lts.parameters.nextInterval(nextAgain.Stability, elapsedDays), *nextAgain, Again, elapsedDays)
+	hardInterval := lts.parameters.modifyInterval(lts.parameters.nextInterval(nextHard.Stability, elapsedDays), *nextHard, Hard, elapsedDays)
+	goodInterval := lts.parameters.modifyInterval(lts.parameters.nextInterval(nextGood.Stability, elapsedDays), *nextGood, Good, elapsedDays)
+	easyInterval := lts.parameters.modifyInterval(lts.parameters.nextInterval(nextEasy.Stability, elapsedDays), *nextEasy, Easy, elapsedDays)
 
 	againInterval = min(againInterval, hardInterval)
 	hardInterval = max(hardInterval, againInterval+1)
 	goodInterval = max(goodInterval, hardInterval+1)
 	easyInterval = max(easyInterval, goodInterval+1)
+	againInterval = lts.parameters.capForExam(againInterval, nextAgain.Stability)
+	hardInterval = lts.parameters.capForExam(hardInterval, nextHard.Stability)
+	goodInterval = lts.parameters.capForExam(goodInterval, nextGood.Stability)
+	easyInterval = lts.parameters.capForExam(easyInterval, nextEasy.Stability)
 
 	nextAgain.ScheduledDays = uint64(againInterval)
-	nextAgain.Due = lts.now.Add(daysToDuration(againInterval, lts.parameters.MaximumInterval))
+	nextAgain.Due = lts.parameters.addDays(lts.now, againInterval)
 
 	nextHard.ScheduledDays = uint64(hardInterval)
-	nextHard.Due = lts.now.Add(daysToDuration(hardInterval, lts.parameters.MaximumInterval))
+	nextHard.Due = lts.parameters.addDays(lts.now, hardInterval)
 
 	nextGood.ScheduledDays = uint64(goodInterval)
-	nextGood.Due = lts.now.Add(daysToDuration(goodInterval, lts.parameters.MaximumInterval))
+	nextGood.Due = lts.parameters.addDays(lts.now, goodInterval)
 
 	nextEasy.ScheduledDays = uint64(easyInterval)
-	nextEasy.Due = lts.now.Add(daysToDuration(easyInterval, lts.parameters.MaximumInterval))
+	nextEasy.Due = lts.parameters.addDays(lts.now, easyInterval)
 }
 
 func setReviewState(nextAgain, nextHard, nextGood, nextEasy *Card) {
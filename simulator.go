@@ -0,0 +1,254 @@
+package fsrs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// SimulatorConfig describes the collection and study habits simulated by a
+// [Simulator]. Costs are in seconds. Rating probabilities need not be
+// normalized. A limit <= 0 means no limit.
+type SimulatorConfig struct {
+	DeckSize         int `json:"DeckSize"`
+	LearnSpan        int `json:"LearnSpan"`
+	MaxNewPerDay     int `json:"MaxNewPerDay"`
+	MaxReviewsPerDay int `json:"MaxReviewsPerDay"`
+	// LearnCosts is the time spent on the first rating of a new card,
+	// indexed by rating - 1.
+	LearnCosts [4]float64 `json:"LearnCosts"`
+	// ReviewCosts is the time spent on every later review, indexed by
+	// rating - 1.
+	ReviewCosts [4]float64 `json:"ReviewCosts"`
+	// FirstRatingProb is the distribution of first ratings, indexed by
+	// rating - 1.
+	FirstRatingProb [4]float64 `json:"FirstRatingProb"`
+	// ReviewRatingProb is the distribution of Hard, Good and Easy among
+	// successful reviews.
+	ReviewRatingProb [3]float64 `json:"ReviewRatingProb"`
+	Start            time.Time  `json:"Start"`
+	Seed             int        `json:"Seed"`
+}
+
+// DefaultSimulatorConfig returns the defaults used by fsrs-rs: 10000 cards
+// learned over a year, 20 new cards and up to 9999 reviews a day, with costs
+// and rating distributions measured on Anki users.
+func DefaultSimulatorConfig() SimulatorConfig {
+	return SimulatorConfig{
+		DeckSize:         10000,
+		LearnSpan:        365,
+		MaxNewPerDay:     20,
+		MaxReviewsPerDay: 9999,
+		LearnCosts:       [4]float64{33.79, 24.3, 13.68, 6.5},
+		ReviewCosts:      [4]float64{23.0, 11.68, 7.33, 5.6},
+		FirstRatingProb:  [4]float64{0.24, 0.094, 0.495, 0.171},
+		ReviewRatingProb: [3]float64{0.224, 0.631, 0.145},
+		Start:            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// SimulationResult holds one entry per simulated day. Memorized is the sum
+// of the retrievability of every introduced card at the end of the day.
+type SimulationResult struct {
+	Reviews   []int     `json:"Reviews"`
+	Learned   []int     `json:"Learned"`
+	Cost      []float64 `json:"Cost"`
+	Memorized []float64 `json:"Memorized"`
+}
+
+// maxSameDayReviews bounds the learning steps simulated for one card in a day.
+const maxSameDayReviews = 16
+
+// Simulator runs a day-by-day Monte Carlo simulation of a collection
+// scheduled with [FSRS.Next].
+type Simulator struct {
+	fsrs   *FSRS
+	config SimulatorConfig
+}
+
+// NewSimulator creates a Simulator scheduling with params. The parameters go
+// through [NewFSRS], so invalid values are clipped or reset the same way.
+func NewSimulator(params Parameters, config SimulatorConfig) *Simulator {
+	return &Simulator{fsrs: NewFSRS(params), config: config}
+}
+
+// Run simulates LearnSpan days. Each day the cards already due are reviewed
+// first, most overdue first, then new cards are introduced. Recall is sampled
+// from the card's retrievability at review time and every card is reviewed
+// again the same day while its learning steps keep it due before midnight.
+func (s *Simulator) Run() (SimulationResult, error) {
+	return s.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops between simulated days and returns
+// ctx.Err() once ctx is cancelled.
+func (s *Simulator) RunContext(ctx context.Context) (SimulationResult, error) {
+	cfg := s.config
+	if cfg.DeckSize < 0 || cfg.LearnSpan <= 0 {
+		return SimulationResult{}, &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: invalid simulator size: DeckSize=%d LearnSpan=%d", cfg.DeckSize, cfg.LearnSpan)}
+	}
+	if sumWeights(cfg.FirstRatingProb[:]) <= 0 || sumWeights(cfg.ReviewRatingProb[:]) <= 0 {
+		return SimulationResult{}, &Error{Code: ErrCodeInvalidInput, Message: "fsrs: simulator rating probabilities must be non-negative with a positive sum"}
+	}
+
+	rng := Alea(cfg.Seed)
+	result := SimulationResult{
+		Reviews:   make([]int, cfg.LearnSpan),
+		Learned:   make([]int, cfg.LearnSpan),
+		Cost:      make([]float64, cfg.LearnSpan),
+		Memorized: make([]float64, cfg.LearnSpan),
+	}
+	cards := make([]Card, 0, cfg.DeckSize)
+
+	for day := 0; day < cfg.LearnSpan; day++ {
+		if err := ctx.Err(); err != nil {
+			return SimulationResult{}, err
+		}
+		dayStart := cfg.Start.Add(time.Duration(day) * 24 * time.Hour)
+		dayEnd := dayStart.Add(24 * time.Hour)
+
+		var due []int
+		for i := range cards {
+			if cards[i].Due.Before(dayEnd) && !cards[i].Suspended {
+				due = append(due, i)
+			}
+		}
+		sort.SliceStable(due, func(a, b int) bool { return cards[due[a]].Due.Before(cards[due[b]].Due) })
+		if cfg.MaxReviewsPerDay > 0 && len(due) > cfg.MaxReviewsPerDay {
+			due = due[:cfg.MaxReviewsPerDay]
+		}
+		for _, i := range due {
+			now := cards[i].Due
+			if now.Before(dayStart) {
+				now = dayStart
+			}
+			if err := s.study(&cards[i], now, dayEnd, rng, &result, day); err != nil {
+				return SimulationResult{}, err
+			}
+		}
+
+		for n := 0; len(cards) < cfg.DeckSize && (cfg.MaxNewPerDay <= 0 || n < cfg.MaxNewPerDay); n++ {
+			cards = append(cards, NewCard(dayStart))
+			if err := s.study(&cards[len(cards)-1], dayStart, dayEnd, rng, &result, day); err != nil {
+				return SimulationResult{}, err
+			}
+			result.Learned[day]++
+		}
+
+		for i := range cards {
+			r, err := s.fsrs.Retrievability(cards[i], dayEnd)
+			if err != nil {
+				return SimulationResult{}, err
+			}
+			result.Memorized[day] += r
+		}
+	}
+	return result, nil
+}
+
+// study reviews card at now and keeps reviewing it while it is due before
+// dayEnd, recording every review and its cost on the given day.
+func (s *Simulator) study(card *Card, now, dayEnd time.Time, rng PRNG, result *SimulationResult, day int) error {
+	for n := 0; n < maxSameDayReviews && now.Before(dayEnd) && !card.Suspended; n++ {
+		var rating Rating
+		var cost float64
+		if card.State == New {
+			rating = Again + Rating(sampleIndex(s.config.FirstRatingProb[:], rng()))
+			cost = s.config.LearnCosts[rating-1]
+		} else {
+			r, err := s.fsrs.Retrievability(*card, now)
+			if err != nil {
+				return err
+			}
+			if rng() < r {
+				rating = Hard + Rating(sampleIndex(s.config.ReviewRatingProb[:], rng()))
+			} else {
+				rating = Again
+			}
+			cost = s.config.ReviewCosts[rating-1]
+		}
+
+		info, err := s.fsrs.Next(*card, now, rating)
+		if err != nil {
+			return err
+		}
+		*card = info.Card
+		result.Reviews[day]++
+		result.Cost[day] += cost
+		now = card.Due
+	}
+	return nil
+}
+
+func sumWeights(weights []float64) float64 {
+	sum := 0.0
+	for _, w := range weights {
+		if !isFinite(w) || w < 0 {
+			return 0
+		}
+		sum += w
+	}
+	return sum
+}
+
+// sampleIndex picks an index of weights with probability proportional to its
+// weight, using u drawn uniformly from [0, 1).
+func sampleIndex(weights []float64, u float64) int {
+	target := u * sumWeights(weights)
+	for i, w := range weights {
+		if target < w {
+			return i
+		}
+		target -= w
+	}
+	return len(weights) - 1
+}
+
+const (
+	minOptimalRetention  = 0.70
+	maxOptimalRetention  = 0.95
+	optimalRetentionStep = 0.01
+)
+
+// OptimalRetention searches RequestRetention over [0.70, 0.95] in steps of
+// 0.01 and returns the value that minimizes the simulated study time per
+// memorized card at the end of the simulation, as fsrs-rs' optimal_retention
+// does. Every candidate is simulated with the same config and seed.
+func OptimalRetention(params Parameters, config SimulatorConfig) (float64, error) {
+	return OptimalRetentionContext(context.Background(), params, config)
+}
+
+// OptimalRetentionContext is like OptimalRetention but stops between
+// candidates and simulated days and returns ctx.Err() once ctx is cancelled.
+func OptimalRetentionContext(ctx context.Context, params Parameters, config SimulatorConfig) (float64, error) {
+	best, bestCost := 0.0, 0.0
+	steps := int(math.Round((maxOptimalRetention - minOptimalRetention) / optimalRetentionStep))
+	for i := 0; i <= steps; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		p := params
+		p.RequestRetention = math.Round((minOptimalRetention+float64(i)*optimalRetentionStep)*100) / 100
+		result, err := NewSimulator(p, config).RunContext(ctx)
+		if err != nil {
+			return 0, err
+		}
+		cost := 0.0
+		for _, c := range result.Cost {
+			cost += c
+		}
+		memorized := result.Memorized[len(result.Memorized)-1]
+		if memorized <= 0 {
+			continue
+		}
+		if perCard := cost / memorized; best == 0 || perCard < bestCost {
+			best, bestCost = p.RequestRetention, perCard
+		}
+	}
+	if best == 0 {
+		return 0, ErrNotEnoughData
+	}
+	return best, nil
+}
@@ -0,0 +1,118 @@
+package fsrs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func smallSimulatorConfig() SimulatorConfig {
+	cfg := DefaultSimulatorConfig()
+	cfg.DeckSize = 200
+	cfg.LearnSpan = 60
+	cfg.MaxNewPerDay = 10
+	return cfg
+}
+
+func TestSimulatorRun(t *testing.T) {
+	cfg := smallSimulatorConfig()
+	result, err := NewSimulator(DefaultParam(), cfg).Run()
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Reviews) != cfg.LearnSpan || len(result.Learned) != cfg.LearnSpan ||
+		len(result.Cost) != cfg.LearnSpan || len(result.Memorized) != cfg.LearnSpan {
+		t.Fatalf("expected %d days in every series, got=%+v", cfg.LearnSpan, result)
+	}
+
+	learned := 0
+	for day, n := range result.Learned {
+		if n > cfg.MaxNewPerDay {
+			t.Errorf("day %d: learned %d cards, limit %d", day, n, cfg.MaxNewPerDay)
+		}
+		learned += n
+	}
+	if learned != cfg.DeckSize {
+		t.Errorf("expected all %d cards learned, got=%d", cfg.DeckSize, learned)
+	}
+	last := result.Memorized[cfg.LearnSpan-1]
+	if last <= 0 || last > float64(cfg.DeckSize) {
+		t.Errorf("expected memorized in (0, %d], got=%v", cfg.DeckSize, last)
+	}
+	if result.Reviews[0] < cfg.MaxNewPerDay || result.Cost[0] <= 0 {
+		t.Errorf("expected first day reviews and cost, got reviews=%d cost=%v", result.Reviews[0], result.Cost[0])
+	}
+
+	again, err := NewSimulator(DefaultParam(), cfg).Run()
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !reflect.DeepEqual(result, again) {
+		t.Errorf("expected deterministic simulation for the same seed")
+	}
+}
+
+func TestSimulatorRetentionIncreasesWorkload(t *testing.T) {
+	cfg := smallSimulatorConfig()
+	totalReviews := func(retention float64) int {
+		p := DefaultParam()
+		p.RequestRetention = retention
+		result, err := NewSimulator(p, cfg).Run()
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		n := 0
+		for _, r := range result.Reviews {
+			n += r
+		}
+		return n
+	}
+	if low, high := totalReviews(0.8), totalReviews(0.95); low >= high {
+		t.Errorf("expected more reviews at higher retention: 0.8=%d 0.95=%d", low, high)
+	}
+}
+
+func TestSimulatorInvalidConfig(t *testing.T) {
+	cfg := smallSimulatorConfig()
+	cfg.LearnSpan = 0
+	_, err := NewSimulator(DefaultParam(), cfg).Run()
+	var fsrsErr *Error
+	if !errors.As(err, &fsrsErr) || fsrsErr.Code != ErrCodeInvalidInput {
+		t.Errorf("expected ErrCodeInvalidInput, got=%v", err)
+	}
+
+	cfg = smallSimulatorConfig()
+	cfg.FirstRatingProb = [4]float64{}
+	_, err = NewSimulator(DefaultParam(), cfg).Run()
+	if !errors.As(err, &fsrsErr) || fsrsErr.Code != ErrCodeInvalidInput {
+		t.Errorf("expected ErrCodeInvalidInput, got=%v", err)
+	}
+}
+
+func TestOptimalRetention(t *testing.T) {
+	cfg := smallSimulatorConfig()
+	cfg.DeckSize = 100
+	got, err := OptimalRetention(DefaultParam(), cfg)
+	if err != nil {
+		t.Fatalf("OptimalRetention returned error: %v", err)
+	}
+	if got < minOptimalRetention || got > maxOptimalRetention {
+		t.Errorf("expected retention in [%v, %v], got=%v", minOptimalRetention, maxOptimalRetention, got)
+	}
+
+	cfg.DeckSize = 0
+	_, err = OptimalRetention(DefaultParam(), cfg)
+	if !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("expected ErrNotEnoughData for an empty deck, got=%v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := OptimalRetentionContext(ctx, DefaultParam(), smallSimulatorConfig()); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got=%v", err)
+	}
+	if _, err := NewSimulator(DefaultParam(), smallSimulatorConfig()).RunContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected RunContext to stop, got=%v", err)
+	}
+}
@@ -0,0 +1,3 @@
+// Package sqlitetest runs the fsrs SQLStore tests against SQLite. It is a
+// separate module so that the fsrs module itself needs no database driver.
+package sqlitetest
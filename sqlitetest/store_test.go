@@ -0,0 +1,192 @@
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	fsrs "github.com/open-spaced-repetition/go-fsrs/v4"
+	_ "modernc.org/sqlite"
+)
+
+func openTestSQLStore(t *testing.T) *fsrs.SQLStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "fsrs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s := fsrs.NewSQLStore(db, fsrs.SQLite)
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate returned error: %v", err)
+	}
+	return s
+}
+
+func TestSQLStore(t *testing.T) {
+	ctx := context.Background()
+	s := openTestSQLStore(t)
+	if err := s.Migrate(ctx); err != nil {
+		t.Errorf("expected Migrate to be idempotent, got=%v", err)
+	}
+	f := fsrs.NewFSRS(fsrs.DefaultParam())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, err := s.LoadCard(ctx, 1); !errors.Is(err, fsrs.ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound, got=%v", err)
+	}
+	card := fsrs.NewCard(now)
+	card.ID = 1
+	if err := s.SaveCard(ctx, card); err != nil {
+		t.Fatalf("SaveCard returned error: %v", err)
+	}
+	info, err := f.ReviewCard(ctx, s, 1, now, fsrs.Good)
+	if err != nil {
+		t.Fatalf("ReviewCard returned error: %v", err)
+	}
+	want, _ := f.Next(card, now, fsrs.Good)
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("expected ReviewCard to match Next:\n got=%+v\nwant=%+v", info, want)
+	}
+
+	loaded, err := s.LoadCard(ctx, 1)
+	if err != nil {
+		t.Fatalf("LoadCard returned error: %v", err)
+	}
+	if !loaded.Due.Equal(info.Card.Due) || loaded.Reps != 1 || loaded.ID != 1 {
+		t.Errorf("expected the reviewed card to be persisted, got=%+v", loaded)
+	}
+
+	_, err = s.Update(ctx, 1, func(fsrs.Card) (fsrs.SchedulingInfo, error) {
+		return fsrs.SchedulingInfo{}, fsrs.ErrInvalidRating
+	})
+	if !errors.Is(err, fsrs.ErrInvalidRating) {
+		t.Errorf("expected the callback error, got=%v", err)
+	}
+
+	if err := s.AppendReviewLog(ctx, 1, fsrs.ReviewLog{Rating: fsrs.Manual, Review: now}); err != nil {
+		t.Fatalf("AppendReviewLog returned error: %v", err)
+	}
+	logs, err := s.ReviewLogs(ctx, 1)
+	if err != nil {
+		t.Fatalf("ReviewLogs returned error: %v", err)
+	}
+	if len(logs) != 2 || logs[0].Rating != fsrs.Good || logs[1].Rating != fsrs.Manual {
+		t.Errorf("expected the review and the manual log, got=%+v", logs)
+	}
+}
+
+func TestSQLStoreConcurrentUpdates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	s := openTestSQLStore(t)
+	f := fsrs.NewFSRS(fsrs.DefaultParam())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	card := fsrs.NewCard(now)
+	card.ID = 1
+	if err := s.SaveCard(ctx, card); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.ReviewCard(ctx, s, 1, now, fsrs.Good); err != nil {
+				errs <- err
+			}
+			if _, err := s.LoadCard(ctx, 1); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent review failed: %v", err)
+	}
+	if card, _ := s.LoadCard(ctx, 1); card.Reps != n {
+		t.Errorf("expected %d reviews applied, got=%d", n, card.Reps)
+	}
+	if logs, _ := s.ReviewLogs(ctx, 1); len(logs) != n {
+		t.Errorf("expected %d logs, got=%d", n, len(logs))
+	}
+}
+
+func TestSQLStoreReviewBatch(t *testing.T) {
+	ctx := context.Background()
+	s := openTestSQLStore(t)
+	f := fsrs.NewFSRS(fsrs.DefaultParam())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	for id := int64(1); id <= 2; id++ {
+		card := fsrs.NewCard(now)
+		card.ID, card.NoteID = id, 7
+		card.ExamDate = now.AddDate(0, 0, 30)
+		card.DesiredRetention = 0.95
+		if err := s.SaveCard(ctx, card); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := s.ReviewBatch(ctx, f, []fsrs.StoredReview{
+		{CardID: 1, Now: now, Rating: fsrs.Good},
+		{CardID: 2, Now: now, Rating: fsrs.Again},
+	})
+	if err != nil {
+		t.Fatalf("ReviewBatch returned error: %v", err)
+	}
+	if len(infos) != 2 || infos[0].ReviewLog.Rating != fsrs.Good || infos[1].ReviewLog.Rating != fsrs.Again {
+		t.Fatalf("expected one result per review, got=%+v", infos)
+	}
+
+	_, err = s.ReviewBatch(ctx, f, []fsrs.StoredReview{
+		{CardID: 1, Now: now.Add(time.Hour), Rating: fsrs.Good},
+		{CardID: 3, Now: now, Rating: fsrs.Good},
+	})
+	if !errors.Is(err, fsrs.ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound, got=%v", err)
+	}
+	card, err := s.LoadCard(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Reps != 1 || card.NoteID != 7 || !card.ExamDate.Equal(now.AddDate(0, 0, 30)) || card.DesiredRetention != 0.95 {
+		t.Errorf("expected the failed batch to be rolled back, got=%+v", card)
+	}
+	if logs, _ := s.ReviewLogs(ctx, 1); len(logs) != 1 || logs[0].DesiredRetention != 0.95 {
+		t.Errorf("expected 1 log recording the card's retention after rollback, got=%+v", logs)
+	}
+}
+
+func TestSQLStoreParameters(t *testing.T) {
+	ctx := context.Background()
+	s := openTestSQLStore(t)
+	if _, err := s.LoadParameters(ctx, "default"); !errors.Is(err, fsrs.ErrParametersNotFound) {
+		t.Fatalf("expected ErrParametersNotFound, got=%v", err)
+	}
+	p := fsrs.DefaultParam()
+	p.Location, _ = time.LoadLocation("Europe/Berlin")
+	p.DayStartHour = 4
+	for _, retention := range []float64{0.8, 0.85} {
+		p.RequestRetention = retention
+		if err := s.SaveParameters(ctx, "default", p); err != nil {
+			t.Fatalf("SaveParameters returned error: %v", err)
+		}
+	}
+	got, err := s.LoadParameters(ctx, "default")
+	if err != nil {
+		t.Fatalf("LoadParameters returned error: %v", err)
+	}
+	if got.RequestRetention != 0.85 || got.W != p.W || got.Location.String() != "Europe/Berlin" || got.DayStartHour != 4 {
+		t.Errorf("expected the last saved parameters, got=%+v", got)
+	}
+}
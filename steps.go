@@ -6,17 +6,27 @@ import (
 )
 
 func dateDiffInDays(last, cur time.Time) uint64 {
-	lr := last.UTC()
-	utc1 := time.Date(lr.Year(), lr.Month(), lr.Day(), 0, 0, 0, 0, time.UTC)
-	n := cur.UTC()
-	utc2 := time.Date(n.Year(), n.Month(), n.Day(), 0, 0, 0, 0, time.UTC)
-	hours := utc2.Sub(utc1).Hours()
+	return dayDiff(last, cur, time.UTC, 0)
+}
+
+// dayDiff counts the review-day boundaries between last and cur, where a
+// review day starts at startHour in loc. Returns 0 when cur precedes last.
+func dayDiff(last, cur time.Time, loc *time.Location, startHour int) uint64 {
+	hours := reviewDay(cur, loc, startHour).Sub(reviewDay(last, loc, startHour)).Hours()
 	if hours < 0 {
 		return 0
 	}
 	return uint64(math.Floor(hours / 24))
 }
 
+// reviewDay returns the calendar date of the review day containing t,
+// expressed as midnight UTC so that differences are whole days even across
+// daylight saving changes in loc.
+func reviewDay(t time.Time, loc *time.Location, startHour int) time.Time {
+	local := t.In(loc).Add(-time.Duration(startHour) * time.Hour)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 func dateDiffRaw(last, cur time.Time) float64 {
 	return math.Floor(cur.Sub(last).Hours() / 24)
 }
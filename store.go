@@ -0,0 +1,128 @@
+package fsrs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Store persists cards keyed by Card.ID together with their review logs.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// LoadCard returns the card with the given ID, or ErrCardNotFound.
+	LoadCard(ctx context.Context, id int64) (Card, error)
+	// SaveCard inserts or replaces the card with ID card.ID.
+	SaveCard(ctx context.Context, card Card) error
+	// AppendReviewLog records a review of the card with the given ID.
+	AppendReviewLog(ctx context.Context, cardID int64, log ReviewLog) error
+	// ReviewLogs returns the review logs of a card in the order they were
+	// appended.
+	ReviewLogs(ctx context.Context, cardID int64) ([]ReviewLog, error)
+	// Update loads the card with the given ID, passes it to fn and, if fn
+	// succeeds, saves the returned card and appends the returned log as a
+	// single atomic step. Returns ErrCardNotFound if the card is missing.
+	Update(ctx context.Context, id int64, fn func(Card) (SchedulingInfo, error)) (SchedulingInfo, error)
+}
+
+// ReviewCard loads the card with the given ID from s, applies [FSRS.Next]
+// with grade at now, and persists the new card and its review log
+// atomically through [Store.Update].
+func (f *FSRS) ReviewCard(ctx context.Context, s Store, id int64, now time.Time, grade Rating) (SchedulingInfo, error) {
+	return s.Update(ctx, id, func(card Card) (SchedulingInfo, error) {
+		return f.Next(card, now, grade)
+	})
+}
+
+// MemoryStore is a Store that keeps everything in memory.
+type MemoryStore struct {
+	mu    sync.Mutex
+	cards map[int64]Card
+	logs  map[int64][]ReviewLog
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		cards: make(map[int64]Card),
+		logs:  make(map[int64][]ReviewLog),
+	}
+}
+
+func (m *MemoryStore) LoadCard(ctx context.Context, id int64) (Card, error) {
+	if err := ctx.Err(); err != nil {
+		return Card{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.load(id)
+}
+
+func (m *MemoryStore) SaveCard(ctx context.Context, card Card) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cards[card.ID] = card
+	return nil
+}
+
+func (m *MemoryStore) AppendReviewLog(ctx context.Context, cardID int64, log ReviewLog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs[cardID] = append(m.logs[cardID], log)
+	return nil
+}
+
+func (m *MemoryStore) ReviewLogs(ctx context.Context, cardID int64) ([]ReviewLog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ReviewLog(nil), m.logs[cardID]...), nil
+}
+
+func (m *MemoryStore) Update(ctx context.Context, id int64, fn func(Card) (SchedulingInfo, error)) (SchedulingInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return SchedulingInfo{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	card, err := m.load(id)
+	if err != nil {
+		return SchedulingInfo{}, err
+	}
+	info, err := fn(card)
+	if err != nil {
+		return SchedulingInfo{}, err
+	}
+	info.Card.ID = id
+	m.apply(jsonlRecord{CardID: id, Card: &info.Card, ReviewLog: &info.ReviewLog})
+	return info, nil
+}
+
+// apply saves the card and appends the log held by rec. The caller must hold
+// m.mu.
+func (m *MemoryStore) apply(rec jsonlRecord) {
+	if rec.Card != nil {
+		m.cards[rec.CardID] = *rec.Card
+	}
+	if rec.ReviewLog != nil {
+		m.logs[rec.CardID] = append(m.logs[rec.CardID], *rec.ReviewLog)
+	}
+}
+
+func (m *MemoryStore) load(id int64) (Card, error) {
+	card, ok := m.cards[id]
+	if !ok {
+		return Card{}, &Error{Code: ErrCodeCardNotFound, Message: fmt.Sprintf("fsrs: card %d not found", id)}
+	}
+	return card, nil
+}
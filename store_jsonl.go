@@ -0,0 +1,167 @@
+package fsrs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// JSONLStore is an append-only Store backed by a JSON-lines file. Every
+// change is appended as one line holding the saved card, the appended review
+// log, or both for [JSONLStore.Update], and synced to disk before it becomes
+// visible. The whole file is replayed into memory when it is opened. A line
+// whose write or sync fails is truncated away again; if that fails too, the
+// store refuses further changes.
+type JSONLStore struct {
+	mem  *MemoryStore
+	file *os.File
+	// err is set when a failed write could not be undone.
+	err error
+}
+
+var _ Store = (*JSONLStore)(nil)
+
+// jsonlRecord is one line of a JSONLStore file.
+type jsonlRecord struct {
+	CardID    int64      `json:"CardID"`
+	Card      *Card      `json:"Card,omitempty"`
+	ReviewLog *ReviewLog `json:"ReviewLog,omitempty"`
+}
+
+// OpenJSONLStore opens or creates the JSON-lines file at path and loads its
+// contents. An incomplete last line, left by a crash during a write, is
+// discarded; any other malformed line is an error.
+func OpenJSONLStore(path string) (*JSONLStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s := &JSONLStore{mem: NewMemoryStore(), file: file}
+	if err := s.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *JSONLStore) replay() error {
+	data, err := os.ReadFile(s.file.Name())
+	if err != nil {
+		return err
+	}
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	if complete < len(data) {
+		if err := s.file.Truncate(int64(complete)); err != nil {
+			return err
+		}
+	}
+	for i, line := range bytes.Split(data[:complete], []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var rec jsonlRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("fsrs: %s line %d: %w", s.file.Name(), i+1, err)
+		}
+		s.mem.apply(rec)
+	}
+	_, err = s.file.Seek(0, io.SeekEnd)
+	return err
+}
+
+// Close closes the underlying file.
+func (s *JSONLStore) Close() error {
+	return s.file.Close()
+}
+
+func (s *JSONLStore) LoadCard(ctx context.Context, id int64) (Card, error) {
+	return s.mem.LoadCard(ctx, id)
+}
+
+func (s *JSONLStore) ReviewLogs(ctx context.Context, cardID int64) ([]ReviewLog, error) {
+	return s.mem.ReviewLogs(ctx, cardID)
+}
+
+func (s *JSONLStore) SaveCard(ctx context.Context, card Card) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+	return s.commit(jsonlRecord{CardID: card.ID, Card: &card})
+}
+
+func (s *JSONLStore) AppendReviewLog(ctx context.Context, cardID int64, log ReviewLog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+	return s.commit(jsonlRecord{CardID: cardID, ReviewLog: &log})
+}
+
+func (s *JSONLStore) Update(ctx context.Context, id int64, fn func(Card) (SchedulingInfo, error)) (SchedulingInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return SchedulingInfo{}, err
+	}
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+	card, err := s.mem.load(id)
+	if err != nil {
+		return SchedulingInfo{}, err
+	}
+	info, err := fn(card)
+	if err != nil {
+		return SchedulingInfo{}, err
+	}
+	info.Card.ID = id
+	if err := s.commit(jsonlRecord{CardID: id, Card: &info.Card, ReviewLog: &info.ReviewLog}); err != nil {
+		return SchedulingInfo{}, err
+	}
+	return info, nil
+}
+
+// commit appends rec as a single line, syncs it and applies it in memory.
+// On failure the file is cut back to where the line started, so no line
+// that was not applied can be followed by later ones. The caller must hold
+// s.mem.mu.
+func (s *JSONLStore) commit(rec jsonlRecord) error {
+	if s.err != nil {
+		return s.err
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if err := s.write(append(line, '\n')); err != nil {
+		if rerr := s.rollback(offset); rerr != nil {
+			s.err = fmt.Errorf("fsrs: %s unusable after a failed write: %w", s.file.Name(), rerr)
+		}
+		return err
+	}
+	s.mem.apply(rec)
+	return nil
+}
+
+func (s *JSONLStore) write(line []byte) error {
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// rollback removes everything written from offset on.
+func (s *JSONLStore) rollback(offset int64) error {
+	if err := s.file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := s.file.Seek(offset, io.SeekStart)
+	return err
+}
@@ -0,0 +1,392 @@
+package fsrs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SQLDialect selects the SQL flavour spoken by a [SQLStore].
+type SQLDialect int8
+
+const (
+	// SQLite uses ? placeholders. SQLite locks the whole database and fails
+	// with SQLITE_BUSY rather than waiting when two transactions try to
+	// write at once, so the store runs one write at a time and keeps reads
+	// out while it does. Other processes sharing the file should set a
+	// busy_timeout.
+	SQLite SQLDialect = iota
+	// Postgres uses $n placeholders and SELECT ... FOR UPDATE row locks.
+	Postgres
+)
+
+// sqlMigrations is the ordered list of schema changes applied by
+// [SQLStore.Migrate]. Entries must never be edited once released; append
+// new ones instead. Times are stored as Unix milliseconds in UTC, with NULL
+// for the zero time.
+var sqlMigrations = []string{
+	`CREATE TABLE fsrs_cards (
+		id              BIGINT PRIMARY KEY,
+		note_id         BIGINT NOT NULL DEFAULT 0,
+		due             BIGINT,
+		stability       DOUBLE PRECISION NOT NULL,
+		difficulty      DOUBLE PRECISION NOT NULL,
+		scheduled_days  BIGINT NOT NULL,
+		reps            BIGINT NOT NULL,
+		lapses          BIGINT NOT NULL,
+		state           SMALLINT NOT NULL,
+		last_review     BIGINT,
+		remaining_steps INTEGER NOT NULL,
+		leech           BOOLEAN NOT NULL DEFAULT FALSE,
+		suspended       BOOLEAN NOT NULL DEFAULT FALSE
+	);
+	CREATE INDEX fsrs_cards_due ON fsrs_cards (due);
+	CREATE INDEX fsrs_cards_note_id ON fsrs_cards (note_id);
+	CREATE TABLE fsrs_revlog (
+		card_id         BIGINT NOT NULL,
+		seq             BIGINT NOT NULL,
+		rating          SMALLINT NOT NULL,
+		due             BIGINT,
+		scheduled_days  BIGINT NOT NULL,
+		review          BIGINT,
+		state           SMALLINT NOT NULL,
+		stability       DOUBLE PRECISION NOT NULL,
+		difficulty      DOUBLE PRECISION NOT NULL,
+		remaining_steps INTEGER NOT NULL,
+		leech           BOOLEAN NOT NULL DEFAULT FALSE,
+		PRIMARY KEY (card_id, seq)
+	);
+	CREATE TABLE fsrs_parameters (
+		profile VARCHAR(255) PRIMARY KEY,
+		data    TEXT NOT NULL
+	)`,
+	`ALTER TABLE fsrs_cards ADD COLUMN exam_date BIGINT`,
+	`ALTER TABLE fsrs_cards ADD COLUMN desired_retention DOUBLE PRECISION NOT NULL DEFAULT 0;
+	ALTER TABLE fsrs_revlog ADD COLUMN desired_retention DOUBLE PRECISION NOT NULL DEFAULT 0`,
+}
+
+// SQLStore is a Store on top of database/sql. The schema, created by
+// [SQLStore.Migrate], consists of:
+//
+//   - fsrs_cards: one row per Card, keyed by id.
+//   - fsrs_revlog: one row per ReviewLog, keyed by (card_id, seq) where seq
+//     numbers the logs of a card from 1 in append order.
+//   - fsrs_parameters: JSON-encoded Parameters keyed by a profile name.
+//   - fsrs_schema_migrations: the migration versions already applied.
+//
+// Times are stored with millisecond precision and read back in UTC.
+type SQLStore struct {
+	db      *sql.DB
+	dialect SQLDialect
+	// mu serializes writes with the SQLite dialect.
+	mu sync.RWMutex
+}
+
+var _ Store = (*SQLStore)(nil)
+
+// NewSQLStore returns a SQLStore using db. Call Migrate before first use.
+func NewSQLStore(db *sql.DB, dialect SQLDialect) *SQLStore {
+	return &SQLStore{db: db, dialect: dialect}
+}
+
+// Migrate brings the schema up to date, applying each pending migration in
+// its own transaction.
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS fsrs_schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+	var version int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM fsrs_schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+	for v := version + 1; v <= len(sqlMigrations); v++ {
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			for _, stmt := range strings.Split(sqlMigrations[v-1], ";") {
+				if strings.TrimSpace(stmt) == "" {
+					continue
+				}
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO fsrs_schema_migrations (version) VALUES (?)`), v)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("fsrs: migration %d: %w", v, err)
+		}
+	}
+	return nil
+}
+
+const sqlCardColumns = `id, note_id, due, stability, difficulty, scheduled_days, reps, lapses, state, last_review, remaining_steps, leech, suspended, exam_date, desired_retention`
+
+func (s *SQLStore) LoadCard(ctx context.Context, id int64) (Card, error) {
+	defer s.lockRead()()
+	return s.loadCard(ctx, s.db, id, false)
+}
+
+func (s *SQLStore) SaveCard(ctx context.Context, card Card) error {
+	defer s.lockWrite()()
+	return s.saveCard(ctx, s.db, card)
+}
+
+// AppendReviewLog appends log to the card's review logs. On Postgres it locks
+// the card row, as Update does, so concurrent appends to a saved card take
+// consecutive sequence numbers.
+func (s *SQLStore) AppendReviewLog(ctx context.Context, cardID int64, log ReviewLog) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if s.dialect == Postgres {
+			if _, err := tx.ExecContext(ctx, s.rebind(`SELECT id FROM fsrs_cards WHERE id = ? FOR UPDATE`), cardID); err != nil {
+				return err
+			}
+		}
+		return s.appendLog(ctx, tx, cardID, log)
+	})
+}
+
+func (s *SQLStore) ReviewLogs(ctx context.Context, cardID int64) ([]ReviewLog, error) {
+	defer s.lockRead()()
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT rating, due, scheduled_days, review, state, stability, difficulty, remaining_steps, leech, desired_retention
+		FROM fsrs_revlog WHERE card_id = ? ORDER BY seq`), cardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var logs []ReviewLog
+	for rows.Next() {
+		var log ReviewLog
+		var due, review sql.NullInt64
+		if err := rows.Scan(&log.Rating, &due, &log.ScheduledDays, &review, &log.State, &log.Stability, &log.Difficulty, &log.RemainingSteps, &log.Leech, &log.DesiredRetention); err != nil {
+			return nil, err
+		}
+		log.Due, log.Review = fromSQLTime(due), fromSQLTime(review)
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+func (s *SQLStore) Update(ctx context.Context, id int64, fn func(Card) (SchedulingInfo, error)) (SchedulingInfo, error) {
+	var info SchedulingInfo
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		info, err = s.update(ctx, tx, id, fn)
+		return err
+	})
+	if err != nil {
+		return SchedulingInfo{}, err
+	}
+	return info, nil
+}
+
+// StoredReview is one review applied by [SQLStore.ReviewBatch].
+type StoredReview struct {
+	CardID int64     `json:"CardID"`
+	Now    time.Time `json:"Now"`
+	Rating Rating    `json:"Rating"`
+}
+
+// ReviewBatch applies [FSRS.Next] to every review in a single transaction,
+// in order, and returns the results in the same order. If any review fails,
+// the transaction is rolled back and nothing is persisted.
+func (s *SQLStore) ReviewBatch(ctx context.Context, f *FSRS, reviews []StoredReview) ([]SchedulingInfo, error) {
+	out := make([]SchedulingInfo, 0, len(reviews))
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, r := range reviews {
+			info, err := s.update(ctx, tx, r.CardID, func(card Card) (SchedulingInfo, error) {
+				return f.Next(card, r.Now, r.Rating)
+			})
+			if err != nil {
+				return fmt.Errorf("fsrs: card %d: %w", r.CardID, err)
+			}
+			out = append(out, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SaveParameters stores p under the given profile name, replacing any
+// previous value. Parameters are stored as JSON, so fields tagged json:"-"
+// are not stored and Location is kept by its IANA name.
+func (s *SQLStore) SaveParameters(ctx context.Context, profile string, p Parameters) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	defer s.lockWrite()()
+	_, err = s.db.ExecContext(ctx, s.rebind(`INSERT INTO fsrs_parameters (profile, data) VALUES (?, ?)
+		ON CONFLICT (profile) DO UPDATE SET data = excluded.data`), profile, string(data))
+	return err
+}
+
+// LoadParameters returns the parameters stored under profile, or
+// ErrParametersNotFound.
+func (s *SQLStore) LoadParameters(ctx context.Context, profile string) (Parameters, error) {
+	defer s.lockRead()()
+	var data string
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT data FROM fsrs_parameters WHERE profile = ?`), profile).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Parameters{}, &Error{Code: ErrCodeParametersNotFound, Message: fmt.Sprintf("fsrs: parameters profile %q not found", profile)}
+	}
+	if err != nil {
+		return Parameters{}, err
+	}
+	var p Parameters
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		return Parameters{}, err
+	}
+	return p, nil
+}
+
+// sqlQuerier is the part of *sql.DB and *sql.Tx used by the store.
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (s *SQLStore) update(ctx context.Context, tx *sql.Tx, id int64, fn func(Card) (SchedulingInfo, error)) (SchedulingInfo, error) {
+	card, err := s.loadCard(ctx, tx, id, true)
+	if err != nil {
+		return SchedulingInfo{}, err
+	}
+	info, err := fn(card)
+	if err != nil {
+		return SchedulingInfo{}, err
+	}
+	info.Card.ID = id
+	if err := s.saveCard(ctx, tx, info.Card); err != nil {
+		return SchedulingInfo{}, err
+	}
+	if err := s.appendLog(ctx, tx, id, info.ReviewLog); err != nil {
+		return SchedulingInfo{}, err
+	}
+	return info, nil
+}
+
+func (s *SQLStore) loadCard(ctx context.Context, q sqlQuerier, id int64, lock bool) (Card, error) {
+	query := `SELECT ` + sqlCardColumns + ` FROM fsrs_cards WHERE id = ?`
+	if lock && s.dialect == Postgres {
+		query += ` FOR UPDATE`
+	}
+	var card Card
+	var due, lastReview, examDate sql.NullInt64
+	err := q.QueryRowContext(ctx, s.rebind(query), id).Scan(
+		&card.ID, &card.NoteID, &due, &card.Stability, &card.Difficulty, &card.ScheduledDays,
+		&card.Reps, &card.Lapses, &card.State, &lastReview, &card.RemainingSteps, &card.Leech, &card.Suspended,
+		&examDate, &card.DesiredRetention,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Card{}, &Error{Code: ErrCodeCardNotFound, Message: fmt.Sprintf("fsrs: card %d not found", id)}
+	}
+	if err != nil {
+		return Card{}, err
+	}
+	card.Due, card.LastReview, card.ExamDate = fromSQLTime(due), fromSQLTime(lastReview), fromSQLTime(examDate)
+	return card, nil
+}
+
+func (s *SQLStore) saveCard(ctx context.Context, q sqlQuerier, card Card) error {
+	_, err := q.ExecContext(ctx, s.rebind(`INSERT INTO fsrs_cards (`+sqlCardColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			note_id = excluded.note_id, due = excluded.due, stability = excluded.stability,
+			difficulty = excluded.difficulty, scheduled_days = excluded.scheduled_days,
+			reps = excluded.reps, lapses = excluded.lapses, state = excluded.state,
+			last_review = excluded.last_review, remaining_steps = excluded.remaining_steps,
+			leech = excluded.leech, suspended = excluded.suspended, exam_date = excluded.exam_date,
+			desired_retention = excluded.desired_retention`),
+		card.ID, card.NoteID, toSQLTime(card.Due), card.Stability, card.Difficulty, int64(card.ScheduledDays),
+		int64(card.Reps), int64(card.Lapses), int16(card.State), toSQLTime(card.LastReview), card.RemainingSteps,
+		card.Leech, card.Suspended, toSQLTime(card.ExamDate), card.DesiredRetention,
+	)
+	return err
+}
+
+func (s *SQLStore) appendLog(ctx context.Context, tx *sql.Tx, cardID int64, log ReviewLog) error {
+	var seq int64
+	if err := tx.QueryRowContext(ctx, s.rebind(`SELECT COALESCE(MAX(seq), 0) + 1 FROM fsrs_revlog WHERE card_id = ?`), cardID).Scan(&seq); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO fsrs_revlog
+		(card_id, seq, rating, due, scheduled_days, review, state, stability, difficulty, remaining_steps, leech, desired_retention)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		cardID, seq, int16(log.Rating), toSQLTime(log.Due), int64(log.ScheduledDays), toSQLTime(log.Review),
+		int16(log.State), log.Stability, log.Difficulty, log.RemainingSteps, log.Leech, log.DesiredRetention,
+	)
+	return err
+}
+
+func (s *SQLStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	defer s.lockWrite()()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockWrite takes the write lock with the SQLite dialect and returns the
+// function that releases it.
+func (s *SQLStore) lockWrite() func() {
+	if s.dialect != SQLite {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// lockRead is like lockWrite for reads, which may run concurrently.
+func (s *SQLStore) lockRead() func() {
+	if s.dialect != SQLite {
+		return func() {}
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// rebind rewrites ? placeholders into the dialect's syntax.
+func (s *SQLStore) rebind(query string) string {
+	if s.dialect != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func toSQLTime(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.UnixMilli(), Valid: true}
+}
+
+func fromSQLTime(v sql.NullInt64) time.Time {
+	if !v.Valid {
+		return time.Time{}
+	}
+	return time.UnixMilli(v.Int64).UTC()
+}
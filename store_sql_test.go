@@ -0,0 +1,17 @@
+package fsrs
+
+import "testing"
+
+// The SQLStore is exercised against SQLite by the sqlitetest module, which
+// keeps the driver out of this module's dependencies.
+
+func TestSQLRebind(t *testing.T) {
+	s := NewSQLStore(nil, Postgres)
+	got := s.rebind(`SELECT a FROM t WHERE b = ? AND c = ?`)
+	if want := `SELECT a FROM t WHERE b = $1 AND c = $2`; got != want {
+		t.Errorf("got=%q want=%q", got, want)
+	}
+	if got := NewSQLStore(nil, SQLite).rebind(`b = ?`); got != `b = ?` {
+		t.Errorf("expected SQLite queries to be left alone, got=%q", got)
+	}
+}
@@ -0,0 +1,170 @@
+package fsrs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+	f := NewFSRS(DefaultParam())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, err := s.LoadCard(ctx, 1); !errors.Is(err, ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound, got=%v", err)
+	}
+	if _, err := f.ReviewCard(ctx, s, 1, now, Good); !errors.Is(err, ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound from ReviewCard, got=%v", err)
+	}
+
+	card := NewCard(now)
+	card.ID = 1
+	if err := s.SaveCard(ctx, card); err != nil {
+		t.Fatalf("SaveCard returned error: %v", err)
+	}
+	info, err := f.ReviewCard(ctx, s, 1, now, Good)
+	if err != nil {
+		t.Fatalf("ReviewCard returned error: %v", err)
+	}
+	want, _ := f.Next(card, now, Good)
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("expected ReviewCard to match Next:\n got=%+v\nwant=%+v", info, want)
+	}
+
+	loaded, err := s.LoadCard(ctx, 1)
+	if err != nil {
+		t.Fatalf("LoadCard returned error: %v", err)
+	}
+	if !loaded.Due.Equal(info.Card.Due) || loaded.Reps != 1 || loaded.ID != 1 {
+		t.Errorf("expected the reviewed card to be persisted, got=%+v", loaded)
+	}
+
+	_, err = s.Update(ctx, 1, func(Card) (SchedulingInfo, error) {
+		return SchedulingInfo{}, ErrInvalidRating
+	})
+	if !errors.Is(err, ErrInvalidRating) {
+		t.Errorf("expected the callback error, got=%v", err)
+	}
+
+	extra := ReviewLog{Rating: Manual, Review: now}
+	if err := s.AppendReviewLog(ctx, 1, extra); err != nil {
+		t.Fatalf("AppendReviewLog returned error: %v", err)
+	}
+	logs, err := s.ReviewLogs(ctx, 1)
+	if err != nil {
+		t.Fatalf("ReviewLogs returned error: %v", err)
+	}
+	if len(logs) != 2 || logs[0].Rating != Good || logs[1].Rating != Manual {
+		t.Errorf("expected the review and the manual log, got=%+v", logs)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestJSONLStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cards.jsonl")
+	s, err := OpenJSONLStore(path)
+	if err != nil {
+		t.Fatalf("OpenJSONLStore returned error: %v", err)
+	}
+	testStore(t, s)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"CardID":1,"Card":{"Du`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	reopened, err := OpenJSONLStore(path)
+	if err != nil {
+		t.Fatalf("reopen returned error: %v", err)
+	}
+	defer reopened.Close()
+	ctx := context.Background()
+	card, err := reopened.LoadCard(ctx, 1)
+	if err != nil {
+		t.Fatalf("LoadCard after reopen returned error: %v", err)
+	}
+	if card.Reps != 1 {
+		t.Errorf("expected the replayed card, got=%+v", card)
+	}
+	logs, err := reopened.ReviewLogs(ctx, 1)
+	if err != nil || len(logs) != 2 {
+		t.Errorf("expected 2 replayed logs, got=%d err=%v", len(logs), err)
+	}
+	if _, err := NewFSRS(DefaultParam()).ReviewCard(ctx, reopened, 1, card.Due, Good); err != nil {
+		t.Fatalf("ReviewCard after reopen returned error: %v", err)
+	}
+
+	again, err := OpenJSONLStore(path)
+	if err != nil {
+		t.Fatalf("expected the truncated tail to leave a valid file, got=%v", err)
+	}
+	defer again.Close()
+	if card, _ := again.LoadCard(ctx, 1); card.Reps != 2 {
+		t.Errorf("expected the review appended after reopen, got=%+v", card)
+	}
+}
+
+func TestJSONLStoreFailedWrite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cards.jsonl")
+	s, err := OpenJSONLStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveCard(ctx, Card{ID: 1, Reps: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A torn line left by a failed write is cut away before the next one.
+	offset, _ := s.file.Seek(0, io.SeekCurrent)
+	s.file.WriteString(`{"CardID":2,"Card":{"Re`)
+	if err := s.rollback(offset); err != nil {
+		t.Fatalf("rollback returned error: %v", err)
+	}
+	if err := s.SaveCard(ctx, Card{ID: 3, Reps: 3}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	reopened, err := OpenJSONLStore(path)
+	if err != nil {
+		t.Fatalf("expected a valid file after rollback, got=%v", err)
+	}
+	if card, err := reopened.LoadCard(ctx, 3); err != nil || card.Reps != 3 {
+		t.Errorf("expected the card written after rollback, got=%+v err=%v", card, err)
+	}
+
+	// When the write cannot be undone the store refuses further changes.
+	readOnly, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened.file.Close()
+	reopened.file = readOnly
+	if err := reopened.SaveCard(ctx, Card{ID: 4}); err == nil {
+		t.Fatal("expected the write to a read-only file to fail")
+	}
+	if reopened.err == nil {
+		t.Error("expected the store to be marked unusable")
+	}
+	if _, err := reopened.LoadCard(ctx, 4); !errors.Is(err, ErrCardNotFound) {
+		t.Errorf("expected the failed change not to be applied, got=%v", err)
+	}
+	reopened.Close()
+}
@@ -18,6 +18,9 @@ func validateCard(card Card, now time.Time) error {
 	if !isValidState(card.State) {
 		return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: invalid card state: %d", card.State)}
 	}
+	if card.Suspended {
+		return ErrCardSuspended
+	}
 	if card.State != New {
 		if !isFinite(card.Stability) || card.Stability < sMin {
 			return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: invalid stability: %v (minimum %v for non-New cards)", card.Stability, sMin)}
@@ -26,6 +29,9 @@ func validateCard(card Card, now time.Time) error {
 			return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: invalid difficulty: %v (minimum %v for non-New cards)", card.Difficulty, dMin)}
 		}
 	}
+	if !isFinite(card.DesiredRetention) || card.DesiredRetention < 0 || card.DesiredRetention > 1 {
+		return &Error{Code: ErrCodeInvalidRetention, Message: fmt.Sprintf("fsrs: invalid DesiredRetention: must be 0 or in (0, 1], got %v", card.DesiredRetention)}
+	}
 	if !card.LastReview.IsZero() && card.LastReview.After(now) {
 		return &Error{Code: ErrCodeInvalidInput, Message: fmt.Sprintf("fsrs: last review date (%v) is after current time (%v)", card.LastReview, now)}
 	}